@@ -0,0 +1,162 @@
+package ansi
+
+import (
+	"image/color"
+	"strconv"
+	"strings"
+)
+
+// Style is the text style set by SGR escape sequences
+type Style struct {
+	// Color is the SGR foreground color, 30-37 or 90-97, or 0 for the
+	// default color
+	Color  int
+	Bold   bool
+	Italic bool
+	Strike bool
+}
+
+// Run is a piece of text in one style
+type Run struct {
+	Text  string
+	Style Style
+}
+
+// Palette holds the RGB value of each SGR foreground color. The colors are
+// mid-tones that read on both light and dark backgrounds.
+var Palette = map[int]color.RGBA{
+	30: {0x3b, 0x3b, 0x3b, 0xff}, 31: {0xcd, 0x31, 0x31, 0xff},
+	32: {0x2e, 0x9b, 0x4f, 0xff}, 33: {0xb5, 0x89, 0x00, 0xff},
+	34: {0x2f, 0x6f, 0xd6, 0xff}, 35: {0xa8, 0x4c, 0xd1, 0xff},
+	36: {0x11, 0x9c, 0xa8, 0xff}, 37: {0xb0, 0xb0, 0xb0, 0xff},
+	90: {0x80, 0x80, 0x80, 0xff}, 91: {0xf1, 0x4c, 0x4c, 0xff},
+	92: {0x23, 0xd1, 0x8b, 0xff}, 93: {0xe5, 0xc0, 0x2e, 0xff},
+	94: {0x3b, 0x8e, 0xea, 0xff}, 95: {0xd6, 0x70, 0xd6, 0xff},
+	96: {0x29, 0xb8, 0xdb, 0xff}, 97: {0xe5, 0xe5, 0xe5, 0xff},
+}
+
+// Parse splits text into lines of styled runs, following SGR escape
+// sequences (bold, italic, strike and the 16 foreground colors). Other
+// escape sequences are dropped. Styles carry over line breaks.
+func Parse(s string) [][]Run {
+	var lines [][]Run
+	var line []Run
+	var text strings.Builder
+	var style Style
+
+	flush := func() {
+		if text.Len() > 0 {
+			line = append(line, Run{Text: text.String(), Style: style})
+			text.Reset()
+		}
+	}
+
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '\n':
+			flush()
+			lines = append(lines, line)
+			line = nil
+		case c == 0x1b:
+			params, final, end := escape(s, i)
+			if final == 'm' {
+				flush()
+				style = style.apply(params)
+			}
+			i = end - 1
+		default:
+			text.WriteByte(c)
+		}
+	}
+	flush()
+	return append(lines, line)
+}
+
+// Strip removes escape sequences from text
+func Strip(s string) string {
+	if !strings.Contains(s, "\x1b") {
+		return s
+	}
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == 0x1b {
+			_, _, end := escape(s, i)
+			i = end - 1
+			continue
+		}
+		sb.WriteByte(s[i])
+	}
+	return sb.String()
+}
+
+// Text returns the text of a line of runs
+func Text(line []Run) string {
+	var sb strings.Builder
+	for _, r := range line {
+		sb.WriteString(r.Text)
+	}
+	return sb.String()
+}
+
+// escape reads the escape sequence starting at i. For a CSI sequence
+// (ESC [ params final) it returns the parameters and final byte; it
+// always returns the index just past the sequence.
+func escape(s string, i int) (params string, final byte, end int) {
+	if i+1 >= len(s) {
+		return "", 0, len(s)
+	}
+	if s[i+1] != '[' {
+		// ESC, any intermediate bytes, and a final byte
+		j := i + 1
+		for j < len(s) && s[j] >= 0x20 && s[j] <= 0x2f {
+			j++
+		}
+		return "", 0, min(j+1, len(s))
+	}
+	for j := i + 2; j < len(s); j++ {
+		if s[j] >= 0x40 && s[j] <= 0x7e {
+			return s[i+2 : j], s[j], j + 1
+		}
+	}
+	return "", 0, len(s)
+}
+
+// apply returns the style after the SGR parameters
+func (st Style) apply(params string) Style {
+	codes := strings.Split(params, ";")
+	for i := 0; i < len(codes); i++ {
+		n, err := strconv.Atoi(codes[i])
+		if err != nil && codes[i] != "" {
+			continue
+		}
+		switch {
+		case n == 0:
+			st = Style{}
+		case n == 1:
+			st.Bold = true
+		case n == 3:
+			st.Italic = true
+		case n == 9:
+			st.Strike = true
+		case n == 22:
+			st.Bold = false
+		case n == 23:
+			st.Italic = false
+		case n == 29:
+			st.Strike = false
+		case n >= 30 && n <= 37, n >= 90 && n <= 97:
+			st.Color = n
+		case n == 39:
+			st.Color = 0
+		case n == 38 || n == 48:
+			// 256-color and RGB colors are not supported; skip their
+			// arguments
+			if i+1 < len(codes) && codes[i+1] == "5" {
+				i += 2
+			} else if i+1 < len(codes) && codes[i+1] == "2" {
+				i += 4
+			}
+		}
+	}
+	return st
+}
@@ -0,0 +1,62 @@
+package ansi
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want [][]Run
+	}{
+		{"plain", "moo", [][]Run{{{Text: "moo"}}}},
+		{
+			"colors and styles",
+			"a \x1b[1;35mb\x1b[0m \x1b[3mc\x1b[23;9md\x1b[m",
+			[][]Run{{
+				{Text: "a "},
+				{Text: "b", Style: Style{Color: 35, Bold: true}},
+				{Text: " "},
+				{Text: "c", Style: Style{Italic: true}},
+				{Text: "d", Style: Style{Strike: true}},
+			}},
+		},
+		{
+			"styles carry over lines",
+			"\x1b[32mx\ny\x1b[39m\n",
+			[][]Run{{{Text: "x", Style: Style{Color: 32}}}, {{Text: "y", Style: Style{Color: 32}}}, nil},
+		},
+		{
+			"other sequences dropped",
+			"\x1b[2J\x1b[38;5;208mhi\x1b[38;2;1;2;3;1m!\x1b(B",
+			[][]Run{{{Text: "hi"}, {Text: "!", Style: Style{Bold: true}}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Parse(tt.in); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse(%q) = %+v, want %+v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStrip(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"moo", "moo"},
+		{"\x1b[35mfunc\x1b[0m main", "func main"},
+		{"cut off \x1b[3", "cut off "},
+	}
+
+	for _, tt := range tests {
+		if got := Strip(tt.in); got != tt.want {
+			t.Errorf("Strip(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -2,28 +2,76 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"image/color"
 	"io/fs"
+	"math"
 	"net/http"
 	"sort"
 	"strconv"
+	"strings"
 
+	"github.com/vnykmshr/gowsay/canvas"
 	"github.com/vnykmshr/gowsay/cow"
+	"github.com/vnykmshr/gowsay/figlet"
+	"github.com/vnykmshr/gowsay/locale"
+	"github.com/vnykmshr/gowsay/message"
+	"github.com/vnykmshr/gowsay/qr"
 	"github.com/vnykmshr/gowsay/web"
 )
 
 // MooRequest represents a request to generate cowsay
 type MooRequest struct {
-	Text    string `json:"text"`
-	Cow     string `json:"cow,omitempty"`
-	Mood    string `json:"mood,omitempty"`
-	Action  string `json:"action,omitempty"`
-	Columns int    `json:"columns,omitempty"`
+	Text    string            `json:"text"`
+	Cow     string            `json:"cow,omitempty"`
+	Mood    string            `json:"mood,omitempty"`
+	Action  string            `json:"action,omitempty"`
+	Columns int               `json:"columns,omitempty"`
+	Seed    *int64            `json:"seed,omitempty"`
+	Vars    map[string]string `json:"vars,omitempty"`
+	Tmpl    bool              `json:"template,omitempty"`
+	Filter  string            `json:"filter,omitempty"`
+	Banner  bool              `json:"banner,omitempty"`
+	Font    string            `json:"font,omitempty"`
+	QR      bool              `json:"qr,omitempty"`
+	QRLevel string            `json:"qr_level,omitempty"`
+	QRQuiet *int              `json:"qr_quiet,omitempty"`
+	QRInv   bool              `json:"qr_invert,omitempty"`
+	Format  string            `json:"format,omitempty"`
+	Color   string            `json:"color,omitempty"`
+	Wear    string            `json:"wear,omitempty"`
+
+	// Image options, for SVG and PNG output
+	FontFamily string `json:"font_family,omitempty"`
+	FontSize   int    `json:"font_size,omitempty"`
+	Scale      int    `json:"scale,omitempty"`
+	Padding    *int   `json:"padding,omitempty"`
+	FG         string `json:"fg,omitempty"`
+	BG         string `json:"bg,omitempty"`
+
+	// HTML output: a color theme, light or dark, for the <pre> block, and
+	// a standalone page instead of a fragment
+	Theme string `json:"theme,omitempty"`
+	Page  bool   `json:"page,omitempty"`
+
+	// Animation, for GIF output: the effects and the number of times to
+	// play, 0 for forever
+	Animate string `json:"animate,omitempty"`
+	Loop    int    `json:"loop,omitempty"`
 }
 
-// MooResponse represents the cowsay output
+// MooResponse represents the cowsay output. Seed is set when the output used
+// randomness; sending it back in a request reproduces the same output.
 type MooResponse struct {
 	Output string `json:"output"`
+	Seed   *int64 `json:"seed,omitempty"`
+}
+
+// DailyResponse represents the cow of the day
+type DailyResponse struct {
+	cow.Daily
+	Output string `json:"output"`
 }
 
 // ErrorResponse represents an error response
@@ -40,11 +88,12 @@ type HealthResponse struct {
 // APIMoo handles /api/moo endpoint - accepts both JSON and query params
 func (m *Module) APIMoo(w http.ResponseWriter, r *http.Request) {
 	var req MooRequest
+	p := m.printer(r)
 
 	// Try to parse JSON body first
 	if r.Header.Get("Content-Type") == "application/json" && r.Method == "POST" {
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-			writeJSONError(w, "Invalid JSON", http.StatusBadRequest)
+			writeJSONError(w, p.Sprintf("Invalid JSON"), http.StatusBadRequest)
 			return
 		}
 	} else {
@@ -53,11 +102,74 @@ func (m *Module) APIMoo(w http.ResponseWriter, r *http.Request) {
 		req.Cow = r.FormValue("cow")
 		req.Mood = r.FormValue("mood")
 		req.Action = r.FormValue("action")
+		req.Filter = r.FormValue("filter")
+		req.Font = r.FormValue("font")
+		req.Banner, _ = strconv.ParseBool(r.FormValue("banner"))
+		req.QR, _ = strconv.ParseBool(r.FormValue("qr"))
+		req.QRLevel = r.FormValue("qr_level")
+		req.Format = r.FormValue("format")
+		req.Color = r.FormValue("color")
+		req.Wear = r.FormValue("wear")
+		req.FontFamily = r.FormValue("font_family")
+		req.FG = r.FormValue("fg")
+		req.BG = r.FormValue("bg")
+		req.Animate = r.FormValue("animate")
+		req.Theme = r.FormValue("theme")
+		req.Page, _ = strconv.ParseBool(r.FormValue("page"))
+		req.Loop, _ = strconv.Atoi(r.FormValue("loop"))
+		if sizeStr := r.FormValue("font_size"); sizeStr != "" {
+			size, err := strconv.Atoi(sizeStr)
+			if err != nil {
+				writeJSONError(w, p.Sprintf("font size must be between %d and %d", canvas.MinFontSize, canvas.MaxFontSize), http.StatusBadRequest)
+				return
+			}
+			req.FontSize = size
+		}
+		if scaleStr := r.FormValue("scale"); scaleStr != "" {
+			scale, err := strconv.Atoi(scaleStr)
+			if err != nil {
+				writeJSONError(w, p.Sprintf("scale must be between 1 and %d", canvas.MaxScale), http.StatusBadRequest)
+				return
+			}
+			req.Scale = scale
+		}
+		if padStr := r.FormValue("padding"); padStr != "" {
+			padding, err := strconv.Atoi(padStr)
+			if err != nil {
+				writeJSONError(w, p.Sprintf("padding must be between 0 and %d", canvas.MaxPadding), http.StatusBadRequest)
+				return
+			}
+			req.Padding = &padding
+		}
+		req.QRInv, _ = strconv.ParseBool(r.FormValue("qr_invert"))
+		if quietStr := r.FormValue("qr_quiet"); quietStr != "" {
+			quiet, err := strconv.Atoi(quietStr)
+			if err != nil {
+				writeJSONError(w, p.Error(qr.ErrQuietZone), http.StatusBadRequest)
+				return
+			}
+			req.QRQuiet = &quiet
+		}
 		if colStr := r.FormValue("columns"); colStr != "" {
 			if col, err := strconv.Atoi(colStr); err == nil && col > 0 {
 				req.Columns = col
 			}
 		}
+		if seedStr := r.FormValue("seed"); seedStr != "" {
+			seed, err := strconv.ParseInt(seedStr, 10, 64)
+			if err != nil {
+				writeJSONError(w, p.Sprintf("seed must be an integer"), http.StatusBadRequest)
+				return
+			}
+			req.Seed = &seed
+		}
+		vars, err := message.ParseVars(r.Form["var"])
+		if err != nil {
+			writeJSONError(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		req.Vars = vars
+		req.Tmpl, _ = strconv.ParseBool(r.FormValue("template"))
 	}
 
 	// Set defaults
@@ -70,46 +182,220 @@ func (m *Module) APIMoo(w http.ResponseWriter, r *http.Request) {
 	if req.Columns == 0 {
 		req.Columns = m.columns
 	}
+	if req.Seed == nil {
+		seed := cow.NewSeed()
+		req.Seed = &seed
+	}
+	rng := cow.NewRand(*req.Seed)
+	sel := m.selector
+	sel.Rand = rng
+	// No-repeat is per Slack user or channel; the seed alone decides API
+	// picks, so echoing it back reproduces the output
+	sel.History = nil
+
+	accessories, err := cow.ParseAccessories(req.Wear)
+	if err != nil {
+		writeJSONError(w, p.Error(err), http.StatusBadRequest)
+		return
+	}
 
-	// Handle random
-	if req.Cow == "random" {
-		req.Cow = cow.RandomCow()
+	// Handle random, optionally restricted to a tag ("random:animal"),
+	// among the cows that can wear the accessories
+	sel.Accessories = accessories
+	if tag, ok := cow.ParseRandom(req.Cow); ok {
+		name, found := sel.RandomTagged(tag)
+		if !found {
+			writeJSONError(w, m.noTaggedCows(p, tag, accessories), http.StatusBadRequest)
+			return
+		}
+		req.Cow = name
 	}
 	if req.Mood == "random" {
-		req.Mood = cow.RandomMood()
+		req.Mood = sel.RandomMood()
 	}
 
 	// Validate
 	if req.Text == "" {
-		writeJSONError(w, "text parameter is required", http.StatusBadRequest)
+		writeJSONError(w, p.Sprintf("text parameter is required"), http.StatusBadRequest)
 		return
 	}
 	if !cow.Exists(req.Cow) {
-		writeJSONError(w, fmt.Sprintf("cow '%s' not found", req.Cow), http.StatusBadRequest)
+		writeJSONError(w, m.cowNotFound(p, req.Cow), http.StatusBadRequest)
+		return
+	}
+	if !m.selector.CowAllowed(req.Cow) {
+		writeJSONError(w, p.Sprintf("cow '%s' is not available in safe mode", req.Cow), http.StatusForbidden)
 		return
 	}
 	if req.Mood != "" && !cow.MoodExists(req.Mood) {
-		writeJSONError(w, fmt.Sprintf("mood '%s' not found", req.Mood), http.StatusBadRequest)
+		writeJSONError(w, m.moodNotFound(p, req.Mood), http.StatusBadRequest)
+		return
+	}
+	if req.Mood != "" && !m.selector.MoodAllowed(req.Mood) {
+		writeJSONError(w, p.Sprintf("mood '%s' is not available in safe mode", req.Mood), http.StatusForbidden)
 		return
 	}
 	if req.Action != cow.ActionSay && req.Action != cow.ActionThink {
 		req.Action = cow.ActionSay
 	}
+	filters, err := cow.ParseFilters(req.Filter)
+	if err != nil {
+		writeJSONError(w, p.Error(err), http.StatusBadRequest)
+		return
+	}
+	if err := cow.CheckAccessories(req.Cow, accessories); err != nil {
+		writeJSONError(w, p.Error(err), http.StatusBadRequest)
+		return
+	}
+	// Images are drawn from ANSI output, keeping its colors and styles
+	image := imageType(r, req.Format)
+	if image != "" && image != imageHTML && len(req.Text) > maxImageText {
+		writeJSONError(w, p.Sprintf("text for images must be at most %d bytes", maxImageText), http.StatusBadRequest)
+		return
+	}
+	format := cow.FormatANSI
+	if image == "" {
+		if format, err = cow.ParseFormat(req.Format); err != nil {
+			writeJSONError(w, p.Sprintf("format '%s' not found", req.Format), http.StatusBadRequest)
+			return
+		}
+	}
+	if req.Color != "" && !cow.ColorExists(req.Color) {
+		writeJSONError(w, p.Sprintf("cow color '%s' not found", req.Color), http.StatusBadRequest)
+		return
+	}
+	imageOpts, err := imageOptions(p, req)
+	if err != nil {
+		writeJSONError(w, p.Error(err), http.StatusBadRequest)
+		return
+	}
+	effects, err := cow.ParseEffects(req.Animate)
+	if err != nil {
+		writeJSONError(w, p.Error(err), http.StatusBadRequest)
+		return
+	}
+	if len(effects) == 0 {
+		effects = []string{cow.EffectBlink, cow.EffectWag}
+	}
+	var font *figlet.Font
+	if req.Banner || req.Font != "" {
+		var ok bool
+		if font, ok = m.font(req.Font); !ok {
+			writeJSONError(w, p.Sprintf("font '%s' not found", req.Font), http.StatusBadRequest)
+			return
+		}
+	}
+	// Templates are opt-in, so text with braces is said as is
+	if req.Tmpl || len(req.Vars) > 0 {
+		text, err := m.template(req.Vars).Expand(req.Text)
+		if err != nil {
+			writeJSONError(w, p.Error(err), http.StatusBadRequest)
+			return
+		}
+		req.Text = text
+	}
+
+	var code *qr.Code
+	if req.QR {
+		if code, err = encodeQR(req); err != nil {
+			writeJSONError(w, p.Error(err), http.StatusBadRequest)
+			return
+		}
+	}
 
-	output := cow.Render([]string{req.Text}, req.Cow, req.Mood, req.Action, req.Columns)
-	writeJSON(w, MooResponse{Output: fmt.Sprintf("```\n%s\n```", output)}, http.StatusOK)
+	renderer := &cow.Renderer{Rand: rng, Filters: filters, Font: font, QR: code, Format: format, Color: req.Color, Accessories: accessories}
+	output := renderer.Render([]string{req.Text}, req.Cow, req.Mood, req.Action, req.Columns)
+	switch image {
+	case imageHTML:
+		w.Header().Set("Content-Type", imageContentTypes[imageHTML])
+		switch {
+		case req.Page:
+			w.Write(canvas.Page(output, imageOpts))
+		case req.Theme != "":
+			w.Write(canvas.HTML(output, imageOpts))
+		default:
+			w.Write(canvas.HTML(output, canvas.Options{}))
+		}
+		return
+	case imageSVG:
+		w.Header().Set("Content-Type", imageContentTypes[imageSVG])
+		w.Write(canvas.SVG(output, imageOpts))
+		return
+	case imagePNG:
+		data, err := canvas.PNG(output, imageOpts)
+		if errors.Is(err, canvas.ErrTooLarge) {
+			writeJSONError(w, p.Error(err), http.StatusBadRequest)
+			return
+		}
+		if err != nil {
+			writeJSONError(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", imageContentTypes[imagePNG])
+		w.Write(data)
+		return
+	case imageGIF:
+		// Frames are the size of the still output, so a frame too large
+		// is caught before animating
+		if s := canvas.Size(output, imageOpts); s.X*s.Y > canvas.MaxPixels {
+			writeJSONError(w, p.Error(canvas.ErrTooLarge), http.StatusBadRequest)
+			return
+		}
+		frames := renderer.Animate(effects, []string{req.Text}, req.Cow, req.Mood, req.Action, req.Columns)
+		data, err := canvas.GIF(frames, req.Loop, imageOpts)
+		if errors.Is(err, canvas.ErrAnimationTooLarge) {
+			writeJSONError(w, p.Error(err), http.StatusBadRequest)
+			return
+		}
+		if err != nil {
+			writeJSONError(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", imageContentTypes[imageGIF])
+		w.Write(data)
+		return
+	}
+	resp := MooResponse{Output: fmt.Sprintf("```\n%s\n```", output)}
+	if rng.Used() {
+		resp.Seed = req.Seed
+	}
+	writeJSON(w, resp, http.StatusOK)
 }
 
-// APICows handles /api/cows endpoint - lists all available cows
+// APICows handles /api/cows endpoint - lists all available cows with their metadata.
+// With q or tag parameters, returns the matching cows ranked by fuzzy score.
 func (m *Module) APICows(w http.ResponseWriter, r *http.Request) {
-	cows := cow.List()
-	sort.Strings(cows)
-	writeJSON(w, map[string][]string{"cows": cows}, http.StatusOK)
+	query := r.FormValue("q")
+	tag := r.FormValue("tag")
+	if query != "" || tag != "" {
+		writeJSON(w, map[string][]cow.Match{"cows": m.selector.Search(query, tag)}, http.StatusOK)
+		return
+	}
+
+	cows := m.selector.ListInfo()
+	sort.Slice(cows, func(i, j int) bool { return cows[i].Name < cows[j].Name })
+	writeJSON(w, map[string][]cow.Info{"cows": cows}, http.StatusOK)
+}
+
+// APIDaily handles /api/daily endpoint - the cow of the day, optionally per namespace.
+// Responses are cacheable until midnight in the configured time zone.
+func (m *Module) APIDaily(w http.ResponseWriter, r *http.Request) {
+	now := m.today()
+	daily := m.localSelector(m.printer(r)).Daily(now, r.FormValue("namespace"))
+	output := cow.Render([]string{daily.Message}, daily.Cow, "", cow.ActionSay, m.columns)
+
+	expires := cow.NextDay(now)
+	maxAge := int(math.Ceil(expires.Sub(now).Seconds()))
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", maxAge))
+	w.Header().Set("Expires", expires.UTC().Format(http.TimeFormat))
+	w.Header().Set("Vary", "Accept-Language")
+
+	writeJSON(w, DailyResponse{Daily: daily, Output: fmt.Sprintf("```\n%s\n```", output)}, http.StatusOK)
 }
 
 // APIMoods handles /api/moods endpoint - lists all available moods
 func (m *Module) APIMoods(w http.ResponseWriter, r *http.Request) {
-	moods := cow.ListMoods()
+	moods := m.selector.ListMoods()
 	sort.Strings(moods)
 	writeJSON(w, map[string][]string{"moods": moods}, http.StatusOK)
 }
@@ -121,6 +407,100 @@ func Health(version string) http.HandlerFunc {
 	}
 }
 
+// cowNotFound describes an unknown cow, suggesting the closest match if there is one
+func (m *Module) cowNotFound(p *locale.Printer, name string) string {
+	if suggestion, ok := m.selector.Suggest(name); ok {
+		return p.Sprintf("cow '%s' not found, did you mean '%s'?", name, suggestion)
+	}
+	return p.Sprintf("cow '%s' not found", name)
+}
+
+// noTaggedCows describes a random:<tag> pick with no cow to pick
+func (m *Module) noTaggedCows(p *locale.Printer, tag string, accessories []string) string {
+	if len(accessories) > 0 {
+		return p.Sprintf("no cows tagged '%s' can wear %s", tag, strings.Join(accessories, ", "))
+	}
+	return p.Sprintf("no cows tagged '%s'", tag)
+}
+
+// encodeQR encodes a request's text as a QR code with its level, quiet
+// zone and colors
+func encodeQR(req MooRequest) (*qr.Code, error) {
+	opts := qr.Options{Level: qr.Medium, QuietZone: qr.DefaultQuietZone, Invert: req.QRInv}
+	if req.QRLevel != "" {
+		level, err := qr.ParseLevel(req.QRLevel)
+		if err != nil {
+			return nil, err
+		}
+		opts.Level = level
+	}
+	if req.QRQuiet != nil {
+		opts.QuietZone = *req.QRQuiet
+	}
+	return qr.Encode(req.Text, opts)
+}
+
+// imageType returns the image format asked for by the format parameter,
+// an /api/moo.svg, .png, .gif or .html path or the Accept header, or "" for
+// JSON. HTML is not taken from Accept, which browsers always send it in.
+func imageType(r *http.Request, format string) string {
+	accept := r.Header.Get("Accept")
+	for _, image := range []string{imageSVG, imagePNG, imageGIF, imageHTML} {
+		if strings.EqualFold(format, image) || strings.HasSuffix(r.URL.Path, "."+image) {
+			return image
+		}
+	}
+	for _, image := range []string{imageSVG, imagePNG, imageGIF} {
+		if strings.Contains(accept, imageContentTypes[image]) {
+			return image
+		}
+	}
+	return ""
+}
+
+// imageOptions returns the image options of a request, with defaults for
+// those not given
+func imageOptions(p *locale.Printer, req MooRequest) (canvas.Options, error) {
+	opts := canvas.DefaultOptions()
+	if req.Theme != "" && !opts.SetTheme(req.Theme) {
+		return opts, errors.New(p.Sprintf("theme '%s' not found", req.Theme))
+	}
+	if req.FontFamily != "" {
+		opts.FontFamily = req.FontFamily
+	}
+	if req.FontSize != 0 {
+		opts.FontSize = req.FontSize
+	}
+	if req.Scale != 0 {
+		opts.Scale = req.Scale
+	}
+	if req.Padding != nil {
+		opts.Padding = *req.Padding
+	}
+	for _, c := range []struct {
+		value string
+		dst   *color.NRGBA
+	}{{req.FG, &opts.Foreground}, {req.BG, &opts.Background}} {
+		if c.value == "" {
+			continue
+		}
+		rgba, err := canvas.ParseColor(c.value)
+		if err != nil {
+			return opts, err
+		}
+		*c.dst = rgba
+	}
+	return opts, opts.Validate()
+}
+
+// moodNotFound describes an unknown mood, suggesting the closest match if there is one
+func (m *Module) moodNotFound(p *locale.Printer, name string) string {
+	if suggestion, ok := m.selector.SuggestMood(name); ok {
+		return p.Sprintf("mood '%s' not found, did you mean '%s'?", name, suggestion)
+	}
+	return p.Sprintf("mood '%s' not found", name)
+}
+
 func writeJSONError(w http.ResponseWriter, message string, statusCode int) {
 	writeJSON(w, ErrorResponse{Error: message}, statusCode)
 }
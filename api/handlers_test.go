@@ -3,10 +3,17 @@ package api
 import (
 	"encoding/json"
 	"fmt"
+	"image/gif"
+	"image/png"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/vnykmshr/gowsay/cow"
+	"github.com/vnykmshr/gowsay/qr"
 )
 
 func TestAPIMoo(t *testing.T) {
@@ -115,7 +122,7 @@ func TestAPICows(t *testing.T) {
 		t.Errorf("status = %d, want %d", w.Code, http.StatusOK)
 	}
 
-	var resp map[string][]string
+	var resp map[string][]cow.Info
 	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
 		t.Fatalf("failed to decode response: %v", err)
 	}
@@ -128,6 +135,12 @@ func TestAPICows(t *testing.T) {
 	if len(cows) < 40 {
 		t.Errorf("expected at least 40 cows, got %d", len(cows))
 	}
+
+	for _, c := range cows {
+		if c.Name == "" || c.Description == "" || c.Rating == "" {
+			t.Errorf("cow %+v is missing metadata", c)
+		}
+	}
 }
 
 func TestAPIMoods(t *testing.T) {
@@ -299,3 +312,699 @@ func TestAPIMoo_Unicode(t *testing.T) {
 		})
 	}
 }
+
+func TestAPIMoo_SafeMode(t *testing.T) {
+	m := &Module{token: "test", columns: 40, selector: cow.Selector{SafeMode: true}}
+
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+	}{
+		{"safe cow", `{"text":"test","cow":"default"}`, http.StatusOK},
+		{"unsafe cow", `{"text":"test","cow":"bong"}`, http.StatusForbidden},
+		{"unsafe mood", `{"text":"test","mood":"stoned"}`, http.StatusForbidden},
+		{"unknown cow", `{"text":"test","cow":"invalid"}`, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/api/moo", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			m.APIMoo(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestAPICows_SafeMode(t *testing.T) {
+	m := &Module{token: "test", columns: 40, selector: cow.Selector{SafeMode: true}}
+	req := httptest.NewRequest("GET", "/api/cows", nil)
+	w := httptest.NewRecorder()
+
+	m.APICows(w, req)
+
+	var resp map[string][]cow.Info
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	for _, c := range resp["cows"] {
+		if c.Rating != cow.RatingSafe {
+			t.Errorf("safe mode listed %s rated %s", c.Name, c.Rating)
+		}
+	}
+}
+
+func TestAPICows_Search(t *testing.T) {
+	m := NewModule()
+	req := httptest.NewRequest("GET", "/api/cows?q=dragon&tag=animal", nil)
+	w := httptest.NewRecorder()
+
+	m.APICows(w, req)
+
+	var resp map[string][]cow.Match
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	cows := resp["cows"]
+	if len(cows) == 0 {
+		t.Fatal("expected matches for q=dragon&tag=animal")
+	}
+	if cows[0].Name != "dragon-and-cow" {
+		t.Errorf("first match = %s, want dragon-and-cow", cows[0].Name)
+	}
+	if cows[0].Score <= 0 {
+		t.Errorf("first match score = %v, want > 0", cows[0].Score)
+	}
+}
+
+func TestAPIMoo_Suggestion(t *testing.T) {
+	m := NewModule()
+	req := httptest.NewRequest("GET", "/api/moo?text=hi&cow=stegasaurus", nil)
+	w := httptest.NewRecorder()
+
+	m.APIMoo(w, req)
+
+	var errResp ErrorResponse
+	if err := json.NewDecoder(w.Body).Decode(&errResp); err != nil {
+		t.Fatalf("failed to decode error response: %v", err)
+	}
+	want := "cow 'stegasaurus' not found, did you mean 'stegosaurus'?"
+	if errResp.Error != want {
+		t.Errorf("error = %q, want %q", errResp.Error, want)
+	}
+}
+
+func TestAPIMoo_Seed(t *testing.T) {
+	m := NewModule()
+
+	moo := func(body string) MooResponse {
+		t.Helper()
+		req := httptest.NewRequest("POST", "/api/moo", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		m.APIMoo(w, req)
+
+		var resp MooResponse
+		if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		return resp
+	}
+
+	first := moo(`{"text":"test","cow":"random","mood":"random"}`)
+	if first.Seed == nil {
+		t.Fatal("random response should echo its seed")
+	}
+
+	again := moo(fmt.Sprintf(`{"text":"test","cow":"random","mood":"random","seed":%d}`, *first.Seed))
+	if again.Output != first.Output {
+		t.Errorf("same seed rendered different output:\n%s\n%s", first.Output, again.Output)
+	}
+
+	if fixed := moo(`{"text":"test","cow":"default"}`); fixed.Seed != nil {
+		t.Errorf("non-random response should not echo a seed, got %d", *fixed.Seed)
+	}
+}
+
+func TestAPIMoo_SeedNoRepeat(t *testing.T) {
+	t.Setenv("GOWSAY_NO_REPEAT", "5")
+	m := NewModule()
+
+	var first string
+	for i := 0; i < 3; i++ {
+		req := httptest.NewRequest("GET", "/api/moo?text=test&cow=random&seed=42", nil)
+		w := httptest.NewRecorder()
+		m.APIMoo(w, req)
+
+		var resp MooResponse
+		if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		if i == 0 {
+			first = resp.Output
+		} else if resp.Output != first {
+			t.Fatalf("request %d with the same seed rendered different output:\n%s\n%s", i+1, first, resp.Output)
+		}
+	}
+	if recent := m.selector.History.Recent(""); len(recent) != 0 {
+		t.Errorf("API picks recorded in history: %v", recent)
+	}
+}
+
+func TestAPIMoo_InvalidSeed(t *testing.T) {
+	m := NewModule()
+	req := httptest.NewRequest("GET", "/api/moo?text=test&seed=abc", nil)
+	w := httptest.NewRecorder()
+
+	m.APIMoo(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
+
+func TestAPIMoo_RandomTag(t *testing.T) {
+	m := NewModule()
+
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+	}{
+		{"known tag", "?text=test&cow=random:holiday", http.StatusOK},
+		{"unknown tag", "?text=test&cow=random:nope", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/api/moo"+tt.query, nil)
+			w := httptest.NewRecorder()
+
+			m.APIMoo(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestAPIDaily(t *testing.T) {
+	tokyo := time.FixedZone("JST", 9*60*60)
+	m := &Module{
+		token:    "test",
+		columns:  40,
+		location: tokyo,
+		now:      func() time.Time { return time.Date(2026, 10, 19, 14, 0, 0, 0, time.UTC) },
+	}
+
+	req := httptest.NewRequest("GET", "/api/daily?namespace=ops", nil)
+	w := httptest.NewRecorder()
+	m.APIDaily(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+
+	// 14:00 UTC is 23:00 in Tokyo, an hour before midnight
+	if got := w.Header().Get("Cache-Control"); got != "public, max-age=3600" {
+		t.Errorf("Cache-Control = %q, want public, max-age=3600", got)
+	}
+	if got := w.Header().Get("Expires"); got != "Mon, 19 Oct 2026 15:00:00 GMT" {
+		t.Errorf("Expires = %q, want Mon, 19 Oct 2026 15:00:00 GMT", got)
+	}
+
+	var resp DailyResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.Date != "2026-10-19" || resp.Namespace != "ops" {
+		t.Errorf("date/namespace = %s/%s, want 2026-10-19/ops", resp.Date, resp.Namespace)
+	}
+	if resp.Cow == "" || resp.Message == "" || !strings.Contains(resp.Output, resp.Message) {
+		t.Errorf("incomplete daily response: %+v", resp)
+	}
+}
+
+func TestAPIMoo_Template(t *testing.T) {
+	m := &Module{
+		token:   "test",
+		columns: 60,
+		now:     func() time.Time { return time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC) },
+	}
+
+	tests := []struct {
+		name       string
+		req        *http.Request
+		wantStatus int
+		want       string
+	}{
+		{
+			name:       "json vars",
+			req:        jsonRequest(`{"text":"deploy #{{build}} is {{status}} on {{date \"Monday\"}}","vars":{"build":"42","status":"green"}}`),
+			wantStatus: http.StatusOK,
+			want:       "deploy #42 is green on Monday",
+		},
+		{
+			name:       "query vars",
+			req:        httptest.NewRequest("GET", "/api/moo?text=%7B%7Buser%7D%7D+says+hi&var=user%3Dalice", nil),
+			wantStatus: http.StatusOK,
+			want:       "alice says hi",
+		},
+		{
+			name:       "opt in",
+			req:        jsonRequest(`{"text":"on {{date \"Monday\"}}","template":true}`),
+			wantStatus: http.StatusOK,
+			want:       "on Monday",
+		},
+		{
+			name:       "not a template",
+			req:        jsonRequest(`{"text":"image: {{ .Values.image }}"}`),
+			wantStatus: http.StatusOK,
+			want:       "image: {{ .Values.image }}",
+		},
+		{
+			name:       "code block",
+			req:        jsonRequest(`{"text":"#{{build}}: ` + "```{{ .Values.image }}```" + `","vars":{"build":"42"}}`),
+			wantStatus: http.StatusOK,
+			want:       "#42: ```{{ .Values.image }}```",
+		},
+		{
+			name:       "undefined variable",
+			req:        jsonRequest(`{"text":"{{build}}","template":true}`),
+			wantStatus: http.StatusBadRequest,
+			want:       `invalid message template: message:1: function \"build\" not defined`,
+		},
+		{
+			name:       "environment not allowed",
+			req:        jsonRequest(`{"text":"{{env \"GOWSAY_TOKEN\"}}","template":true}`),
+			wantStatus: http.StatusBadRequest,
+			want:       "not allowed",
+		},
+		{
+			name:       "bad var",
+			req:        httptest.NewRequest("GET", "/api/moo?text=hi&var=nope", nil),
+			wantStatus: http.StatusBadRequest,
+			want:       "name=value",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			m.APIMoo(w, tt.req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if !strings.Contains(w.Body.String(), tt.want) {
+				t.Errorf("body = %s, want it to contain %s", w.Body.String(), tt.want)
+			}
+		})
+	}
+}
+
+func jsonRequest(body string) *http.Request {
+	req := httptest.NewRequest("POST", "/api/moo", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	return req
+}
+
+func TestAPIMoo_Locale(t *testing.T) {
+	m := &Module{token: "test", columns: 40}
+
+	tests := []struct {
+		name           string
+		acceptLanguage string
+		defaultLocale  string
+		want           string
+	}{
+		{"english", "", "", "cow 'nope' not found"},
+		{"japanese", "ja-JP,ja;q=0.9", "", "牛 'nope' が見つかりません"},
+		{"brazilian portuguese", "pt-BR", "", "vaca 'nope' não encontrada"},
+		{"default locale", "fr", "ja", "牛 'nope' が見つかりません"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m.locale = tt.defaultLocale
+			req := httptest.NewRequest("GET", "/api/moo?text=hi&cow=nope", nil)
+			req.Header.Set("Accept-Language", tt.acceptLanguage)
+			w := httptest.NewRecorder()
+			m.APIMoo(w, req)
+
+			var resp ErrorResponse
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if resp.Error != tt.want {
+				t.Errorf("error = %q, want %q", resp.Error, tt.want)
+			}
+		})
+	}
+}
+
+func TestAPIMoo_Filter(t *testing.T) {
+	m := &Module{token: "test", columns: 40}
+
+	tests := []struct {
+		name       string
+		req        *http.Request
+		wantStatus int
+		want       string
+	}{
+		{"query", httptest.NewRequest("GET", "/api/moo?text=hello+you&filter=moo,upper", nil), http.StatusOK, "HAY MOO"},
+		{"json", jsonRequest(`{"text":"Hello","filter":"rot13"}`), http.StatusOK, "Uryyb"},
+		{"redact", jsonRequest(`{"text":"password=hunter2","filter":"redact"}`), http.StatusOK, "password=[REDACTED]"},
+		{"unknown", jsonRequest(`{"text":"Hello","filter":"upper,nope"}`), http.StatusBadRequest, "filter 'nope' not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			m.APIMoo(w, tt.req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if !strings.Contains(w.Body.String(), tt.want) {
+				t.Errorf("body = %s, want it to contain %s", w.Body.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestAPIMoo_Wear(t *testing.T) {
+	m := &Module{token: "test", columns: 40}
+
+	tests := []struct {
+		name       string
+		req        *http.Request
+		wantStatus int
+		want       string
+	}{
+		{"query", httptest.NewRequest("GET", "/api/moo?text=hello&wear=sunglasses", nil), http.StatusOK, "(##)"},
+		{"json", jsonRequest(`{"text":"Hello","cow":"tux","wear":"santa-hat"}`), http.StatusOK, "(______)"},
+		{"unknown", jsonRequest(`{"text":"Hello","wear":"cape"}`), http.StatusBadRequest, "accessory 'cape' not found"},
+		{"missing anchor", jsonRequest(`{"text":"Hello","cow":"tux","wear":"sunglasses"}`), http.StatusBadRequest, "cow 'tux' has no eyes anchor for 'sunglasses'"},
+		{"same anchor", jsonRequest(`{"text":"Hello","cow":"koala","wear":"santa-hat,party-hat"}`), http.StatusBadRequest, "accessories 'santa-hat' and 'party-hat' both go on the head anchor"},
+		{"no random wearer", jsonRequest(`{"text":"Hello","cow":"random:holiday","wear":"mug"}`), http.StatusBadRequest, "no cows tagged 'holiday' can wear mug"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			m.APIMoo(w, tt.req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if !strings.Contains(w.Body.String(), tt.want) {
+				t.Errorf("body = %s, want it to contain %s", w.Body.String(), tt.want)
+			}
+		})
+	}
+
+	// Random cows are picked among those that can wear the accessories
+	for seed := 1; seed <= 20; seed++ {
+		w := httptest.NewRecorder()
+		m.APIMoo(w, httptest.NewRequest("GET", fmt.Sprintf("/api/moo?text=hi&cow=random&wear=santa-hat,sunglasses&seed=%d", seed), nil))
+		if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "(______)") {
+			t.Errorf("random cow with seed %d: status = %d: %s", seed, w.Code, w.Body.String())
+		}
+	}
+}
+
+func TestAPIMoo_Banner(t *testing.T) {
+	m := &Module{token: "test", columns: 40}
+
+	tests := []struct {
+		name       string
+		req        *http.Request
+		wantStatus int
+		want       string
+	}{
+		{"query", httptest.NewRequest("GET", "/api/moo?text=ok&banner=true", nil), http.StatusOK, "# #"},
+		{"font", jsonRequest(`{"text":"ok","font":"block"}`), http.StatusOK, "██"},
+		{"filter first", jsonRequest(`{"text":"hello you","banner":true,"font":"mini","filter":"moo"}`), http.StatusOK, "▀"},
+		{"unknown", jsonRequest(`{"text":"ok","font":"nope"}`), http.StatusBadRequest, "font 'nope' not found"},
+		{"no paths", jsonRequest(`{"text":"ok","font":"../figlet/fonts/small.flf"}`), http.StatusBadRequest, "not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			m.APIMoo(w, tt.req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if !strings.Contains(w.Body.String(), tt.want) {
+				t.Errorf("body = %s, want it to contain %s", w.Body.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestAPIMoo_QR(t *testing.T) {
+	m := &Module{token: "test", columns: 40}
+	code, err := qr.Encode("https://example.com", qr.Options{Level: qr.High, QuietZone: 0, Invert: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		req        *http.Request
+		wantStatus int
+		want       string
+	}{
+		{"query", httptest.NewRequest("GET", "/api/moo?text=https://example.com&qr=true", nil), http.StatusOK, "▄"},
+		{"json", jsonRequest(`{"text":"https://example.com","qr":true,"qr_level":"H","qr_quiet":0,"qr_invert":true}`), http.StatusOK, code.Lines()[3]},
+		{"bad level", httptest.NewRequest("GET", "/api/moo?text=moo&qr=true&qr_level=X", nil), http.StatusBadRequest, "QR level must be L, M, Q or H"},
+		{"bad quiet zone", httptest.NewRequest("GET", "/api/moo?text=moo&qr=true&qr_quiet=99", nil), http.StatusBadRequest, "between 0 and 16"},
+		{"too long", jsonRequest(`{"text":"` + strings.Repeat("moo", 1000) + `","qr":true}`), http.StatusBadRequest, "too long"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			m.APIMoo(w, tt.req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			var resp map[string]string
+			json.NewDecoder(w.Body).Decode(&resp)
+			if !strings.Contains(resp["output"]+resp["error"], tt.want) {
+				t.Errorf("response = %v, want it to contain %q", resp, tt.want)
+			}
+		})
+	}
+}
+
+func TestAPIMoo_Format(t *testing.T) {
+	m := &Module{token: "test", columns: 40}
+
+	tests := []struct {
+		name       string
+		req        *http.Request
+		wantStatus int
+		want       string
+	}{
+		{"text", jsonRequest("{\"text\":\"see:\\n```go\\nfunc main() {}\\n```\"}"), http.StatusOK, `\ func main() {} /`},
+		{"ansi", jsonRequest("{\"text\":\"```go\\nfunc main() {}\\n```\",\"format\":\"ansi\"}"), http.StatusOK, "\x1b[35mfunc\x1b[0m"},
+		{"unknown", jsonRequest(`{"text":"moo","format":"nope"}`), http.StatusBadRequest, "format 'nope' not found"},
+		{"color", jsonRequest(`{"text":"moo","cow":"cow-braille","format":"ansi","color":"cyan"}`), http.StatusOK, "\x1b[36m    \\\x1b[0m"},
+		{"unknown color", jsonRequest(`{"text":"moo","color":"mauve"}`), http.StatusBadRequest, "cow color 'mauve' not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			m.APIMoo(w, tt.req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			var resp map[string]string
+			json.NewDecoder(w.Body).Decode(&resp)
+			if !strings.Contains(resp["output"]+resp["error"], tt.want) {
+				t.Errorf("response = %v, want it to contain %q", resp, tt.want)
+			}
+		})
+	}
+}
+
+func TestAPIMoo_HTML(t *testing.T) {
+	m := &Module{token: "test", columns: 40}
+
+	tests := []struct {
+		name       string
+		req        *http.Request
+		wantStatus int
+		want       []string
+		wantNot    string
+	}{
+		{"fragment", httptest.NewRequest("GET", "/api/moo?text=a<b&format=html", nil), http.StatusOK,
+			[]string{`<pre class="gowsay">`, "&lt; a&lt;b &gt;"}, "style="},
+		{"theme", httptest.NewRequest("GET", "/api/moo.html?text=*moo*&theme=dark", nil), http.StatusOK,
+			[]string{"background:#0d1117", `<span style="font-weight:bold">moo</span>`}, "<!DOCTYPE html>"},
+		{"page", jsonRequest(`{"text":"moo","format":"html","page":true}`), http.StatusOK,
+			[]string{"<!DOCTYPE html>", "background:#ffffff", "navigator.clipboard"}, ""},
+		{"bad theme", httptest.NewRequest("GET", "/api/moo.html?text=moo&theme=sepia", nil), http.StatusBadRequest,
+			[]string{"theme 'sepia' not found"}, ""},
+		{"font family", httptest.NewRequest("GET", "/api/moo.html?text=moo&theme=dark&font_family="+url.QueryEscape(`"Fira Code", monospace`), nil), http.StatusOK,
+			[]string{"font-family:&#34;Fira Code&#34;, monospace;"}, ""},
+		{"css injection", httptest.NewRequest("GET", "/api/moo.html?text=moo&page=1&font_family="+url.QueryEscape("x;background:url(//evil)"), nil), http.StatusBadRequest,
+			[]string{"font family 'x;background:url(//evil)' may only have letters, digits, spaces, commas, hyphens and quotes"}, "url(//evil)</"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			m.APIMoo(w, tt.req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if got := w.Header().Get("Content-Type"); tt.wantStatus == http.StatusOK && got != "text/html; charset=utf-8" {
+				t.Errorf("Content-Type = %s, want text/html", got)
+			}
+			body := w.Body.String()
+			for _, want := range tt.want {
+				if !strings.Contains(body, want) {
+					t.Errorf("body = %s, want it to contain %q", body, want)
+				}
+			}
+			if tt.wantNot != "" && strings.Contains(body, tt.wantNot) {
+				t.Errorf("body = %s, want no %q", body, tt.wantNot)
+			}
+		})
+	}
+}
+
+func TestAPIMoo_SVG(t *testing.T) {
+	m := &Module{token: "test", columns: 40}
+
+	svgAccept := httptest.NewRequest("GET", "/api/moo?text=moo", nil)
+	svgAccept.Header.Set("Accept", "image/svg+xml,image/*")
+
+	tests := []struct {
+		name       string
+		req        *http.Request
+		wantStatus int
+		wantType   string
+		want       string
+	}{
+		{"path", httptest.NewRequest("GET", "/api/moo.svg?text=a<b", nil), http.StatusOK, "image/svg+xml", "&lt; a&lt;b &gt;"},
+		{"accept", svgAccept, http.StatusOK, "image/svg+xml", "&lt; moo &gt;"},
+		{"format", jsonRequest(`{"text":"*bold*","format":"svg","bg":"transparent","fg":"#f00","font_size":20,"padding":0}`), http.StatusOK, "image/svg+xml", `font-size="20" fill="#ff0000"`},
+		{"styles kept", jsonRequest(`{"text":"*bold*","format":"svg"}`), http.StatusOK, "image/svg+xml", `font-weight="bold">bold</tspan>`},
+		{"json by default", httptest.NewRequest("GET", "/api/moo?text=moo", nil), http.StatusOK, "application/json", `"output"`},
+		{"bad color", httptest.NewRequest("GET", "/api/moo.svg?text=moo&bg=blue", nil), http.StatusBadRequest, "application/json", "color 'blue'"},
+		{"bad size", httptest.NewRequest("GET", "/api/moo.svg?text=moo&font_size=999", nil), http.StatusBadRequest, "application/json", "font size must be between 4 and 96"},
+		{"bad padding", httptest.NewRequest("GET", "/api/moo.svg?text=moo&padding=x", nil), http.StatusBadRequest, "application/json", "padding must be between 0 and 256"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			m.APIMoo(w, tt.req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if got := w.Header().Get("Content-Type"); got != tt.wantType {
+				t.Errorf("Content-Type = %s, want %s", got, tt.wantType)
+			}
+			if !strings.Contains(w.Body.String(), tt.want) {
+				t.Errorf("body = %s, want it to contain %s", w.Body.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestAPIMoo_PNG(t *testing.T) {
+	m := &Module{token: "test", columns: 40}
+
+	pngAccept := httptest.NewRequest("GET", "/api/moo?text=moo", nil)
+	pngAccept.Header.Set("Accept", "image/png")
+
+	long := strings.Repeat("moo ", 1000)
+
+	tests := []struct {
+		name       string
+		req        *http.Request
+		wantStatus int
+		wantWidth  int
+		wantError  string
+	}{
+		{"path", httptest.NewRequest("GET", "/api/moo.png?text=moo&padding=0", nil), http.StatusOK, 28 * 12, ""},
+		{"accept", pngAccept, http.StatusOK, 2*16 + 28*12, ""},
+		{"scale", jsonRequest(`{"text":"moo","format":"png","scale":1,"padding":0,"bg":"transparent"}`), http.StatusOK, 28 * 6, ""},
+		{"bad scale", httptest.NewRequest("GET", "/api/moo.png?text=moo&scale=9", nil), http.StatusBadRequest, 0, "scale must be between 1 and 8"},
+		{"too many pixels", jsonRequest(`{"text":"` + long + `","columns":200,"scale":8,"format":"png"}`), http.StatusBadRequest, 0, "image must be at most 8388608 pixels"},
+		{"text too long", jsonRequest(`{"text":"` + long + long + `","format":"png"}`), http.StatusBadRequest, 0, "text for images must be at most 4096 bytes"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			m.APIMoo(w, tt.req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				if !strings.Contains(w.Body.String(), tt.wantError) {
+					t.Errorf("body = %s, want it to contain %s", w.Body.String(), tt.wantError)
+				}
+				return
+			}
+			if got := w.Header().Get("Content-Type"); got != "image/png" {
+				t.Errorf("Content-Type = %s, want image/png", got)
+			}
+			img, err := png.Decode(w.Body)
+			if err != nil {
+				t.Fatalf("body is not a PNG: %v", err)
+			}
+			if got := img.Bounds().Dx(); got != tt.wantWidth {
+				t.Errorf("width = %d, want %d", got, tt.wantWidth)
+			}
+		})
+	}
+}
+
+func TestAPIMoo_GIF(t *testing.T) {
+	m := &Module{token: "test", columns: 40}
+
+	tests := []struct {
+		name       string
+		req        *http.Request
+		wantStatus int
+		wantFrames int
+		wantError  string
+	}{
+		{"path", httptest.NewRequest("GET", "/api/moo.gif?text=moo", nil), http.StatusOK, 9, ""},
+		{"format", jsonRequest(`{"text":"moo","format":"gif","animate":"type","loop":1}`), http.StatusOK, 4, ""},
+		{"bad animation", httptest.NewRequest("GET", "/api/moo.gif?text=moo&animate=spin", nil), http.StatusBadRequest, 0, "animation 'spin' not found"},
+		{"frame too large", jsonRequest(`{"text":"` + strings.Repeat("moo ", 400) + `","columns":200,"scale":8,"format":"gif"}`), http.StatusBadRequest, 0, "image must be at most 8388608 pixels"},
+		{"too many pixels", jsonRequest(`{"text":"` + strings.Repeat("moo ", 100) + `","columns":200,"scale":4,"format":"gif","animate":"type,blink"}`), http.StatusBadRequest, 0, "animation must be at most 33554432 pixels in all frames"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			m.APIMoo(w, tt.req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				if !strings.Contains(w.Body.String(), tt.wantError) {
+					t.Errorf("body = %s, want it to contain %s", w.Body.String(), tt.wantError)
+				}
+				return
+			}
+			if got := w.Header().Get("Content-Type"); got != "image/gif" {
+				t.Errorf("Content-Type = %s, want image/gif", got)
+			}
+			anim, err := gif.DecodeAll(w.Body)
+			if err != nil {
+				t.Fatalf("body is not a GIF: %v", err)
+			}
+			if got := len(anim.Image); got != tt.wantFrames {
+				t.Errorf("frames = %d, want %d", got, tt.wantFrames)
+			}
+		})
+	}
+}
@@ -7,26 +7,29 @@ import (
 	"strings"
 
 	"github.com/vnykmshr/gowsay/cow"
+	"github.com/vnykmshr/gowsay/locale"
 )
 
 // GetBanner returns the startup banner with usage information
-func GetBanner(version string) string {
-	return fmt.Sprintf("gowsay [%s][%s]\n%s\n%s", version, os.Getenv(envKey), GetUsageString(), GetHelpString())
+func GetBanner(version string, sel cow.Selector) string {
+	return fmt.Sprintf("gowsay [%s][%s]\n%s\n%s", version, os.Getenv(envKey), GetUsageString(nil), GetHelpString(sel, nil))
 }
 
-// GetUsageString returns the usage string
-func GetUsageString() string {
-	return fmt.Sprintf("Usage: `/moo [%s|surprise] [cow] [mood] message`", cow.ActionThink)
+// GetUsageString returns the usage string in the printer's language
+func GetUsageString(p *locale.Printer) string {
+	return p.Sprintf("Usage: `/moo [filter:<names>] [banner[:<font>]] [wear:<accessories>] [template] [%s|surprise] [cow] [mood] message`", cow.ActionThink)
 }
 
-// GetHelpString returns the help string with available cows and moods
-func GetHelpString() string {
-	cows := append([]string{"`" + commandRandom + "`"}, formatList(cow.List())...)
-	moods := append([]string{"`" + commandRandom + "`"}, formatList(cow.ListMoods())...)
+// GetHelpString returns the help string with the cows and moods the selector
+// allows and the filters, in the printer's language
+func GetHelpString(sel cow.Selector, p *locale.Printer) string {
+	cows := append([]string{"`" + commandRandom + "`", "`" + commandRandom + ":<tag>`"}, formatList(sel.List())...)
+	moods := append([]string{"`" + commandRandom + "`"}, formatList(sel.ListMoods())...)
+	filters := formatList(cow.ListFilters())
 	sort.Strings(cows)
 	sort.Strings(moods)
 
-	return fmt.Sprintf("Cows: %s\nMoods: %s", strings.Join(cows, ", "), strings.Join(moods, ", "))
+	return p.Sprintf("Cows: %s\nMoods: %s\nFilters: %s", strings.Join(cows, ", "), strings.Join(moods, ", "), strings.Join(filters, ", "))
 }
 
 func formatList(items []string) []string {
@@ -8,7 +8,7 @@ import (
 )
 
 func TestGetUsageString(t *testing.T) {
-	usage := GetUsageString()
+	usage := GetUsageString(nil)
 
 	if !strings.Contains(usage, "Usage:") {
 		t.Error("Usage string should contain 'Usage:' label")
@@ -28,7 +28,7 @@ func TestGetUsageString(t *testing.T) {
 }
 
 func TestGetHelpString(t *testing.T) {
-	help := GetHelpString()
+	help := GetHelpString(cow.Selector{}, nil)
 
 	if !strings.Contains(help, "Cows:") {
 		t.Error("Help string should contain 'Cows:' section")
@@ -54,13 +54,17 @@ func TestGetHelpString(t *testing.T) {
 	if !strings.Contains(help, "`"+commandRandom+"`") {
 		t.Error("Help should contain 'random' option")
 	}
+
+	if !strings.Contains(help, "Filters:") || !strings.Contains(help, "`"+cow.FilterUpper+"`") {
+		t.Error("Help should list the filters")
+	}
 }
 
 func TestGetBanner(t *testing.T) {
 	testVersion := "v1.2.3-test"
 	t.Setenv(envKey, "testing")
 
-	banner := GetBanner(testVersion)
+	banner := GetBanner(testVersion, cow.Selector{})
 
 	sections := []string{
 		"gowsay",
@@ -90,3 +94,16 @@ func TestGetBanner(t *testing.T) {
 		t.Error("First line should contain environment in brackets")
 	}
 }
+
+func TestGetHelpString_SafeMode(t *testing.T) {
+	help := GetHelpString(cow.Selector{SafeMode: true}, nil)
+
+	for _, name := range []string{"bong", "mutilated", "stoned"} {
+		if strings.Contains(help, "`"+name+"`") {
+			t.Errorf("Safe mode help should not contain '%s'", name)
+		}
+	}
+	if !strings.Contains(help, "`default`") {
+		t.Error("Safe mode help should still contain 'default'")
+	}
+}
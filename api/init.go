@@ -5,10 +5,17 @@ import (
 	"log/slog"
 	"net/http"
 	"os"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/vnykmshr/gowsay/cow"
+	"github.com/vnykmshr/gowsay/figlet"
+	"github.com/vnykmshr/gowsay/fortune"
+	"github.com/vnykmshr/gowsay/locale"
+	"github.com/vnykmshr/gowsay/message"
 )
 
 // NewModule creates a new API handler module with configuration from environment
@@ -25,37 +32,234 @@ func NewModule() *Module {
 		}
 	}
 
+	safeMode, _ := strconv.ParseBool(os.Getenv("GOWSAY_SAFE_MODE"))
+	selector := cow.Selector{
+		SafeMode: safeMode,
+		Exclude:  cow.ParseList(os.Getenv("GOWSAY_EXCLUDE")),
+	}
+
+	if weights, err := cow.ParseWeights(os.Getenv("GOWSAY_WEIGHTS")); err == nil {
+		selector.Weights = weights
+	} else {
+		slog.Warn("ignoring GOWSAY_WEIGHTS", "error", err)
+	}
+
+	if path := os.Getenv("GOWSAY_FORTUNE_PATH"); path != "" {
+		if packs, err := fortune.LoadPath(path); err == nil {
+			selector.Messages = fortune.Messages(packs)
+		} else {
+			slog.Warn("ignoring GOWSAY_FORTUNE_PATH", "error", err)
+		}
+	}
+
+	if window, err := strconv.Atoi(os.Getenv("GOWSAY_NO_REPEAT")); err == nil && window > 0 {
+		selector.History = cow.NewHistory(window)
+	}
+
+	repeatScope := scopeChannel
+	if os.Getenv("GOWSAY_NO_REPEAT_SCOPE") == scopeUser {
+		repeatScope = scopeUser
+	}
+
+	location := time.Local
+	if tz := os.Getenv("GOWSAY_TIMEZONE"); tz != "" {
+		if loc, err := time.LoadLocation(tz); err == nil {
+			location = loc
+		} else {
+			slog.Warn("ignoring GOWSAY_TIMEZONE", "error", err)
+		}
+	}
+
+	fonts := make(map[string]*figlet.Font)
+	if dir := os.Getenv("GOWSAY_FONT_DIR"); dir != "" {
+		if loaded, err := figlet.LoadDir(dir); err == nil {
+			for _, f := range loaded {
+				fonts[f.Name] = f
+			}
+		} else {
+			slog.Warn("ignoring GOWSAY_FONT_DIR", "error", err)
+		}
+	}
+
 	return &Module{
-		token:   token,
-		columns: columns,
+		token:        token,
+		columns:      columns,
+		selector:     selector,
+		repeatScope:  repeatScope,
+		location:     location,
+		now:          time.Now,
+		templateEnv:  cow.ParseList(os.Getenv("GOWSAY_TEMPLATE_ENV")),
+		templateDir:  os.Getenv("GOWSAY_TEMPLATE_DIR"),
+		locale:       locale.Match(os.Getenv("GOWSAY_LOCALE")),
+		slackLocales: parseSlackLocales(os.Getenv("GOWSAY_SLACK_LOCALES")),
+		fonts:        fonts,
+	}
+}
+
+// parseSlackLocales parses per-workspace languages, e.g. "T0123=ja,T0456=pt-BR"
+func parseSlackLocales(s string) map[string]string {
+	locales := make(map[string]string)
+	for _, entry := range cow.ParseList(s) {
+		team, lang, ok := strings.Cut(entry, "=")
+		if !ok {
+			slog.Warn("ignoring GOWSAY_SLACK_LOCALES entry", "entry", entry)
+			continue
+		}
+		locales[strings.TrimSpace(team)] = locale.Match(lang)
 	}
+	return locales
+}
+
+// Selector returns the cow selector configured for the module
+func (m *Module) Selector() cow.Selector {
+	return m.selector
+}
+
+// today returns the current time in the module's time zone
+func (m *Module) today() time.Time {
+	now := time.Now
+	if m.now != nil {
+		now = m.now
+	}
+	loc := m.location
+	if loc == nil {
+		loc = time.Local
+	}
+	return now().In(loc)
+}
+
+// printer returns the printer for an API request: the Accept-Language
+// header's best supported language, or the default locale
+func (m *Module) printer(r *http.Request) *locale.Printer {
+	prefs := append(locale.ParseAcceptLanguage(r.Header.Get("Accept-Language")), m.locale)
+	return locale.NewPrinter(locale.Match(prefs...))
+}
+
+// slackPrinter returns the printer for a Slack request: the workspace's
+// language, or the default locale
+func (m *Module) slackPrinter(r *http.Request) *locale.Printer {
+	if lang, ok := m.slackLocales[r.FormValue(fieldTeamID)]; ok {
+		return locale.NewPrinter(lang)
+	}
+	return locale.NewPrinter(m.locale)
+}
+
+// localSelector returns the module's selector, drawing random messages in
+// the printer's language unless fortune packs are configured
+func (m *Module) localSelector(p *locale.Printer) cow.Selector {
+	sel := m.selector
+	if len(sel.Messages) == 0 {
+		sel.Messages = p.Messages()
+	}
+	return sel
+}
+
+// font returns the banner font with the given name, or the default font
+// if name is empty. Fonts from GOWSAY_FONT_DIR take precedence over the
+// embedded ones; fonts are never loaded from request-supplied paths.
+func (m *Module) font(name string) (*figlet.Font, bool) {
+	if name == "" {
+		name = figlet.DefaultFont
+	}
+	if f, ok := m.fonts[name]; ok {
+		return f, true
+	}
+	return figlet.Builtin(name)
+}
+
+// fontNames returns the names of the available banner fonts, sorted
+func (m *Module) fontNames() []string {
+	names := figlet.Builtins()
+	for name := range m.fonts {
+		if !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// template returns the message template for a request with the given variables
+func (m *Module) template(vars map[string]string) message.Template {
+	return message.Template{Vars: vars, Now: m.today(), Env: m.templateEnv, Dir: m.templateDir}
 }
 
 // Gowsay handles Slack /moo command requests
 func (m *Module) Gowsay(w http.ResponseWriter, r *http.Request) {
+	p := m.slackPrinter(r)
+
 	token := r.FormValue(fieldToken)
 	if os.Getenv(envKey) == envProduction && token != m.token && token != defaultTokenValue {
-		m.motd(w)
+		m.motd(w, p)
 		return
 	}
 
 	text := r.FormValue(fieldText)
 	if strings.TrimSpace(text) == "" {
-		m.motd(w)
+		m.motd(w, p)
 		return
 	}
 
 	parts := sanitize(strings.Split(text, " "))
 	if len(parts) == 0 {
-		m.motd(w)
+		m.motd(w, p)
 		return
 	}
+	words := len(parts)
+
+	rng := cow.NewRand(cow.NewSeed())
+	sel := m.localSelector(p)
+	sel.Rand = rng
+	sel.Key = r.FormValue(fieldChannelID)
+	if m.repeatScope == scopeUser {
+		sel.Key = r.FormValue(fieldUserID)
+	}
+
+	var filters []cow.Filter
+	if names, ok := strings.CutPrefix(parts[0], commandFilter+":"); ok && len(parts) > 1 {
+		var err error
+		if filters, err = cow.ParseFilters(names); err != nil {
+			m.ephemeral(w, p.Error(err))
+			return
+		}
+		parts = parts[1:]
+	}
+
+	var font *figlet.Font
+	if name, ok := strings.CutPrefix(parts[0], commandBanner); ok && (name == "" || name[0] == ':') && len(parts) > 1 {
+		name = strings.TrimPrefix(name, ":")
+		if font, ok = m.font(name); !ok {
+			m.ephemeral(w, p.Sprintf("font '%s' not found", name))
+			return
+		}
+		parts = parts[1:]
+	}
+
+	var accessories []string
+	if names, ok := strings.CutPrefix(parts[0], commandWear+":"); ok && len(parts) > 1 {
+		var err error
+		if accessories, err = cow.ParseAccessories(names); err != nil {
+			m.ephemeral(w, p.Error(err))
+			return
+		}
+		parts = parts[1:]
+	}
+	// Random cows are picked among those that can wear the accessories
+	sel.Accessories = accessories
+
+	// Templates are opt-in, so text with braces is said as is
+	tmpl := false
+	if len(parts) > 1 && parts[0] == commandTemplate {
+		tmpl = true
+		parts = parts[1:]
+	}
 
 	if len(parts) == 1 && (parts[0] == commandList || parts[0] == commandHelp) {
 		writeJSON(w, SlackResponse{
 			ResponseType: responseEphemeral,
-			Text:         GetUsageString(),
-			Attachments:  []Attachment{{Text: GetHelpString()}},
+			Text:         GetUsageString(p),
+			Attachments: []Attachment{{Text: GetHelpString(m.selector, p) + "\n" + p.Sprintf("Fonts: %s", strings.Join(formatList(m.fontNames()), ", ")) +
+				"\n" + p.Sprintf("Accessories: %s", strings.Join(formatList(cow.Accessories()), ", "))}},
 		}, http.StatusOK)
 		return
 	}
@@ -63,9 +267,24 @@ func (m *Module) Gowsay(w http.ResponseWriter, r *http.Request) {
 	if len(parts) > 0 && parts[0] == commandSurprise {
 		parts = parts[1:]
 		if len(parts) == 0 {
-			parts = []string{cow.RandomMessage()}
+			parts = []string{sel.RandomMessage()}
+		} else if strings.Contains(text, codeFence) {
+			parts = []string{rawTail(text, words-len(parts))}
+		}
+		if tmpl {
+			var err error
+			if parts, err = m.slackTemplate(r, parts); err != nil {
+				m.ephemeral(w, p.Error(err))
+				return
+			}
 		}
-		output := cow.Render(parts, cow.RandomCow(), cow.RandomMood(), cow.ActionSay, m.columns)
+		cowName, mood := sel.RandomCow(), sel.RandomMood()
+		if err := cow.CheckAccessories(cowName, accessories); err != nil {
+			m.ephemeral(w, p.Error(err))
+			return
+		}
+		slog.Info("slack command", "command", "/moo", "action", commandSurprise, "cow", cowName, "mood", mood, "seed", rng.Seed())
+		output := (&cow.Renderer{Rand: rng, Filters: filters, Font: font, Accessories: accessories}).Render(parts, cowName, mood, cow.ActionSay, m.columns)
 		writeJSON(w, SlackResponse{ResponseType: responseInChannel, Text: fmt.Sprintf("```\n%s\n```", output)}, http.StatusOK)
 		return
 	}
@@ -80,40 +299,116 @@ func (m *Module) Gowsay(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if len(parts) > 1 {
-		if cow.Exists(parts[0]) {
-			cowName = parts[0]
+		// A word that is not a cow is the start of the message, even if it
+		// looks like one ("yes" and "eyes"); only an explicit cow:<name>
+		// is an unknown cow, with a suggestion
+		name, explicit := strings.CutPrefix(parts[0], commandCow+":")
+		if !explicit {
+			name = parts[0]
+		}
+		if explicit && !cow.Exists(name) {
+			m.ephemeral(w, m.cowNotFound(p, name))
+			return
+		}
+		if cow.Exists(name) {
+			if !m.selector.CowAllowed(name) {
+				m.ephemeral(w, p.Sprintf("cow '%s' is not available in safe mode", name))
+				return
+			}
+			cowName = name
 			parts = parts[1:]
-		} else if parts[0] == commandRandom {
-			cowName = cow.RandomCow()
+		} else if tag, ok := cow.ParseRandom(parts[0]); ok {
+			name, found := sel.RandomTagged(tag)
+			if !found {
+				m.ephemeral(w, m.noTaggedCows(p, tag, accessories))
+				return
+			}
+			cowName = name
 			parts = parts[1:]
 		}
 
 		if len(parts) > 0 && cow.MoodExists(parts[0]) {
+			if !m.selector.MoodAllowed(parts[0]) {
+				m.ephemeral(w, p.Sprintf("mood '%s' is not available in safe mode", parts[0]))
+				return
+			}
 			mood = parts[0]
 			parts = parts[1:]
 		} else if len(parts) > 0 && parts[0] == commandRandom {
-			mood = cow.RandomMood()
+			mood = sel.RandomMood()
 			parts = parts[1:]
 		}
 	}
 
 	if len(parts) == 0 {
-		parts = append(parts, cow.RandomMessage())
+		parts = append(parts, sel.RandomMessage())
+	} else {
+		if strings.Contains(text, codeFence) {
+			parts = []string{rawTail(text, words-len(parts))}
+		}
+		if tmpl {
+			var err error
+			if parts, err = m.slackTemplate(r, parts); err != nil {
+				m.ephemeral(w, p.Error(err))
+				return
+			}
+		}
+	}
+
+	if err := cow.CheckAccessories(cowName, accessories); err != nil {
+		m.ephemeral(w, p.Error(err))
+		return
 	}
 
-	slog.Info("slack command", "command", "/moo", "action", action, "cow", cowName, "mood", mood, "text", strings.Join(parts, " "))
-	output := cow.Render(parts, cowName, mood, action, m.columns)
+	logArgs := []any{"command", "/moo", "action", action, "cow", cowName, "mood", mood, "text", strings.Join(parts, " ")}
+	if rng.Used() {
+		logArgs = append(logArgs, "seed", rng.Seed())
+	}
+	slog.Info("slack command", logArgs...)
+	output := (&cow.Renderer{Rand: rng, Filters: filters, Font: font, Accessories: accessories}).Render(parts, cowName, mood, action, m.columns)
 	writeJSON(w, SlackResponse{ResponseType: responseInChannel, Text: fmt.Sprintf("```\n%s\n```", output)}, http.StatusOK)
 }
 
-func (m *Module) motd(w http.ResponseWriter) {
-	motd := cow.Render([]string{cow.RandomMessage()}, cow.RandomCow(), cow.RandomMood(), cow.ActionSay, m.columns)
+// slackTemplate expands a message written as a template, with the Slack
+// user and channel as variables
+func (m *Module) slackTemplate(r *http.Request, parts []string) ([]string, error) {
+	return m.template(map[string]string{
+		message.VarUser:    r.FormValue(fieldUserName),
+		message.VarChannel: r.FormValue(fieldChannelName),
+	}).ExpandLines(parts)
+}
+
+// ephemeral replies to the Slack user only, without posting to the channel
+func (m *Module) ephemeral(w http.ResponseWriter, text string) {
+	writeJSON(w, SlackResponse{ResponseType: responseEphemeral, Text: text}, http.StatusOK)
+}
+
+func (m *Module) motd(w http.ResponseWriter, p *locale.Printer) {
+	sel := m.localSelector(p)
+	sel.History = nil
+	motd := cow.Render([]string{sel.RandomMessage()}, sel.RandomCow(), sel.RandomMood(), cow.ActionSay, m.columns)
 	_, err := w.Write([]byte(motd))
 	if err != nil {
 		slog.Error("failed to write motd response", "error", err)
 	}
 }
 
+// rawTail returns the text after its first n words, as counted by
+// sanitize, with spacing and line breaks kept for code blocks
+func rawTail(text string, n int) string {
+	offset := 0
+	for _, s := range strings.Split(text, " ") {
+		if strings.TrimSpace(s) != "" {
+			if n == 0 {
+				return text[offset:]
+			}
+			n--
+		}
+		offset += len(s) + 1
+	}
+	return ""
+}
+
 func sanitize(s []string) []string {
 	var r []string
 	for _, str := range s {
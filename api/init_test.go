@@ -4,11 +4,16 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"os"
+	"path/filepath"
 	"reflect"
+	"slices"
+	"strings"
 	"testing"
 
 	"github.com/vnykmshr/gowsay/cow"
+	"github.com/vnykmshr/gowsay/figlet"
 )
 
 func TestModule_Gowsay(t *testing.T) {
@@ -135,19 +140,337 @@ func TestModule_Gowsay(t *testing.T) {
 	}
 }
 
+func TestModule_Gowsay_Rejected(t *testing.T) {
+	t.Setenv(envKey, "")
+	m := &Module{token: "abc123", columns: 40, selector: cow.Selector{SafeMode: true}}
+
+	tests := []struct {
+		name     string
+		text     string
+		wantType string
+	}{
+		{"unsafe cow", "bong%20hello", responseEphemeral},
+		{"unsafe mood", "default%20stoned%20hello", responseEphemeral},
+		{"safe cow", "tux%20hello", responseInChannel},
+		{"misspelled cow", "cow:stegasaurus%20hello", responseEphemeral},
+		{"explicit cow", "cow:tux%20hello", responseInChannel},
+		{"unknown tag", "random:nope%20hello", responseEphemeral},
+		{"known tag", "random:holiday%20hello", responseInChannel},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("GET", "http://localhost/say?token=abc123&text="+tt.text, nil)
+			m.Gowsay(w, r)
+
+			var resp SlackResponse
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if resp.ResponseType != tt.wantType {
+				t.Errorf("response type = %s, want %s (%s)", resp.ResponseType, tt.wantType, resp.Text)
+			}
+		})
+	}
+}
+
+func TestModule_Gowsay_WordsLikeCows(t *testing.T) {
+	t.Setenv(envKey, "")
+	m := &Module{token: "abc123", columns: 40}
+
+	// First words close to a cow name are said by the default cow
+	for _, text := range []string{"yes we shipped", "sleep well team", "funny story", "cheers everyone", "red alert", "stegasaurus hello"} {
+		t.Run(text, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("GET", "http://localhost/say?token=abc123&text="+url.QueryEscape(text), nil)
+			m.Gowsay(w, r)
+
+			var resp SlackResponse
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if resp.ResponseType != responseInChannel || !strings.Contains(resp.Text, strings.Fields(text)[0]) || !strings.Contains(resp.Text, "^__^") {
+				t.Errorf("response = %+v, want the default cow saying %q", resp, text)
+			}
+		})
+	}
+
+	w := httptest.NewRecorder()
+	m.Gowsay(w, httptest.NewRequest("GET", "http://localhost/say?token=abc123&text="+url.QueryEscape("cow:stegasaurus hello"), nil))
+	if !strings.Contains(w.Body.String(), "did you mean 'stegosaurus'?") {
+		t.Errorf("cow:stegasaurus response = %s, want a suggestion", w.Body.String())
+	}
+}
+
+func TestModule_Gowsay_NoRepeat(t *testing.T) {
+	t.Setenv(envKey, "")
+	m := &Module{
+		token:       "abc123",
+		columns:     40,
+		selector:    cow.Selector{History: cow.NewHistory(1)},
+		repeatScope: scopeChannel,
+	}
+
+	for i := 0; i < 20; i++ {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "http://localhost/say?token=abc123&channel_id=C1&text=random:holiday%20hi", nil)
+		m.Gowsay(w, r)
+	}
+
+	recent := m.selector.History.Recent("C1")
+	if len(recent) != 1 {
+		t.Fatalf("Recent(C1) = %v, want one pick", recent)
+	}
+	if other := m.selector.History.Recent(""); len(other) != 0 {
+		t.Errorf("picks leaked to empty key: %v", other)
+	}
+}
+
+func TestModule_Gowsay_Template(t *testing.T) {
+	t.Setenv(envKey, "")
+	m := &Module{token: "abc123", columns: 60}
+
+	say := func(text string) SlackResponse {
+		t.Helper()
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "http://localhost/say?token=abc123&user_name=alice&channel_name=ops&text="+text, nil)
+		m.Gowsay(w, r)
+		var resp SlackResponse
+		if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		return resp
+	}
+
+	resp := say("template%20tux%20hi%20{{user}}%20in%20{{channel}}")
+	if !strings.Contains(resp.Text, "alice") || !strings.Contains(resp.Text, "ops") {
+		t.Errorf("Slack user and channel should be expanded, got:\n%s", resp.Text)
+	}
+
+	// Without the template keyword, and in code blocks, braces are said as written
+	for _, text := range []string{"{{user}}", "tux%20{{hostname}}", "{{%20.Values.image%20}}", "template%20look%20```%0A{{user}}%0A```"} {
+		resp := say(text)
+		if resp.ResponseType != responseInChannel || strings.Contains(resp.Text, "alice") || !strings.Contains(resp.Text, "{{") {
+			t.Errorf("%s: response %s, want it said as written:\n%s", text, resp.ResponseType, resp.Text)
+		}
+	}
+
+	// Templates that do not expand are reported to the user only
+	resp = say("template%20{{nope}}")
+	if resp.ResponseType != responseEphemeral || !strings.Contains(resp.Text, "invalid message template") {
+		t.Errorf("bad template: response %s %q, want an ephemeral template error", resp.ResponseType, resp.Text)
+	}
+}
+
+func TestModule_Gowsay_Filter(t *testing.T) {
+	t.Setenv(envKey, "")
+	m := &Module{token: "abc123", columns: 40}
+
+	say := func(text string) SlackResponse {
+		t.Helper()
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "http://localhost/say?token=abc123&text="+url.QueryEscape(text), nil)
+		m.Gowsay(w, r)
+
+		var resp SlackResponse
+		if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		return resp
+	}
+
+	if resp := say("filter:upper,leet tux hello"); !strings.Contains(resp.Text, "H3LL0") {
+		t.Errorf("filtered output:\n%s", resp.Text)
+	}
+	if resp := say("filter:upper surprise"); resp.ResponseType != responseInChannel {
+		t.Errorf("filtered surprise response type = %s", resp.ResponseType)
+	}
+	if resp := say("filter:nope hello"); resp.ResponseType != responseEphemeral || !strings.Contains(resp.Text, "nope") {
+		t.Errorf("unknown filter response = %+v", resp)
+	}
+}
+
+func TestModule_Gowsay_Banner(t *testing.T) {
+	t.Setenv(envKey, "")
+	m := &Module{token: "abc123", columns: 40}
+
+	say := func(text string) SlackResponse {
+		t.Helper()
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "http://localhost/say?token=abc123&text="+url.QueryEscape(text), nil)
+		m.Gowsay(w, r)
+
+		var resp SlackResponse
+		if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		return resp
+	}
+
+	resp := say("banner SHIPPED")
+	if resp.ResponseType != responseInChannel || !strings.Contains(resp.Text, "###") || strings.Contains(resp.Text, "SHIPPED") {
+		t.Errorf("banner output:\n%s", resp.Text)
+	}
+	if resp := say("filter:upper banner:block tux ok"); !strings.Contains(resp.Text, "██") || !strings.Contains(resp.Text, "(|     | )") {
+		t.Errorf("banner with font, filter and cow:\n%s", resp.Text)
+	}
+	if resp := say("banner:nope hello"); resp.ResponseType != responseEphemeral || !strings.Contains(resp.Text, "font 'nope' not found") {
+		t.Errorf("unknown font response = %+v", resp)
+	}
+	if resp := say("banner"); !strings.Contains(resp.Text, "banner") {
+		t.Errorf("a lone banner keyword should be the message:\n%s", resp.Text)
+	}
+	if resp := say("help"); !strings.Contains(resp.Attachments[0].Text, "`small`") {
+		t.Errorf("help should list fonts: %+v", resp)
+	}
+}
+
+func TestModule_Gowsay_Wear(t *testing.T) {
+	t.Setenv(envKey, "")
+	m := &Module{token: "abc123", columns: 40}
+
+	say := func(text string) SlackResponse {
+		t.Helper()
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "http://localhost/say?token=abc123&text="+url.QueryEscape(text), nil)
+		m.Gowsay(w, r)
+
+		var resp SlackResponse
+		if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		return resp
+	}
+
+	if resp := say("wear:party-hat,sunglasses think moo"); resp.ResponseType != responseInChannel || !strings.Contains(resp.Text, "/___\\") || !strings.Contains(resp.Text, "(##)") {
+		t.Errorf("wear output:\n%s", resp.Text)
+	}
+	if resp := say("filter:upper wear:mug tux hi"); !strings.Contains(resp.Text, "HI") || !strings.Contains(resp.Text, "c[_]\\___)") {
+		t.Errorf("wear with filter and cow:\n%s", resp.Text)
+	}
+	if resp := say("wear:cape hello"); resp.ResponseType != responseEphemeral || !strings.Contains(resp.Text, "accessory 'cape' not found") {
+		t.Errorf("unknown accessory response = %+v", resp)
+	}
+	if resp := say("wear:sunglasses tux hello"); resp.ResponseType != responseEphemeral || !strings.Contains(resp.Text, "cow 'tux' has no eyes anchor for 'sunglasses'") {
+		t.Errorf("missing anchor response = %+v", resp)
+	}
+	if resp := say("wear:santa-hat,party-hat koala hello"); resp.ResponseType != responseEphemeral || !strings.Contains(resp.Text, "both go on the head anchor") {
+		t.Errorf("same anchor response = %+v", resp)
+	}
+	if resp := say("wear:mug random:holiday hello"); resp.ResponseType != responseEphemeral || !strings.Contains(resp.Text, "no cows tagged 'holiday' can wear mug") {
+		t.Errorf("no random wearer response = %+v", resp)
+	}
+
+	// Random cows are picked among those that can wear the accessories
+	for i := 0; i < 20; i++ {
+		for _, text := range []string{"wear:santa-hat,sunglasses surprise hi", "wear:santa-hat,sunglasses random hi"} {
+			if resp := say(text); resp.ResponseType != responseInChannel || !strings.Contains(resp.Text, "(______)") {
+				t.Fatalf("%s response = %+v", text, resp)
+			}
+		}
+	}
+	if resp := say("help"); !strings.Contains(resp.Attachments[0].Text, "`santa-hat`") {
+		t.Errorf("help should list accessories: %+v", resp)
+	}
+}
+
+func TestModule_Gowsay_CodeBlock(t *testing.T) {
+	t.Setenv(envKey, "")
+	m := &Module{token: "abc123", columns: 40}
+
+	say := func(text string) SlackResponse {
+		t.Helper()
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "http://localhost/say?token=abc123&text="+url.QueryEscape(text), nil)
+		m.Gowsay(w, r)
+
+		var resp SlackResponse
+		if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		return resp
+	}
+
+	resp := say("tux  look at this:\n```sh\nfor f in *; do\n    echo  $f\ndone\n```")
+	for _, want := range []string{"/ look at this:", "|     echo  $f", "\\ done", "(|     | )"} {
+		if !strings.Contains(resp.Text, want) {
+			t.Errorf("code block output missing %q:\n%s", want, resp.Text)
+		}
+	}
+	if resp := say("surprise ```\n a  b\n```"); !strings.Contains(resp.Text, "<  a  b >") {
+		t.Errorf("surprise code block:\n%s", resp.Text)
+	}
+	if resp := say("tux *ship* it _now_"); !strings.Contains(resp.Text, "/ ship \\") || strings.Contains(resp.Text, "*") {
+		t.Errorf("markdown markers should be stripped:\n%s", resp.Text)
+	}
+}
+
+func TestModule_Gowsay_SlackLocale(t *testing.T) {
+	t.Setenv(envKey, "")
+	m := &Module{token: "abc123", columns: 40, slackLocales: map[string]string{"T0JP": "ja"}}
+
+	help := func(team string) SlackResponse {
+		t.Helper()
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "http://localhost/say?token=abc123&text=help&team_id="+team, nil)
+		m.Gowsay(w, r)
+
+		var resp SlackResponse
+		if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		return resp
+	}
+
+	if resp := help("T0JP"); !strings.HasPrefix(resp.Text, "使い方") || !strings.HasPrefix(resp.Attachments[0].Text, "牛:") {
+		t.Errorf("Japanese workspace help = %q / %q", resp.Text, resp.Attachments[0].Text)
+	}
+	if resp := help("T0US"); !strings.HasPrefix(resp.Text, "Usage") {
+		t.Errorf("other workspace help = %q, want English", resp.Text)
+	}
+}
+
+func Test_parseSlackLocales(t *testing.T) {
+	got := parseSlackLocales("T0JP=ja_JP, T0BR=pt-BR, broken")
+	want := map[string]string{"T0JP": "ja", "T0BR": "pt-BR"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseSlackLocales() = %v, want %v", got, want)
+	}
+}
+
 func TestModule_motd(t *testing.T) {
 	m := &Module{
 		token:   "test",
 		columns: 40,
 	}
 	w := httptest.NewRecorder()
-	m.motd(w)
+	m.motd(w, nil)
 
 	if w.Code == 0 {
 		t.Error("motd should set status code")
 	}
 }
 
+func TestModule_motd_Fortunes(t *testing.T) {
+	t.Setenv("GOWSAY_FORTUNE_PATH", "../fortune/testdata/packs/farm")
+	m := NewModule()
+	if len(m.selector.Messages) != 3 {
+		t.Fatalf("loaded %d fortunes, want 3", len(m.selector.Messages))
+	}
+
+	w := httptest.NewRecorder()
+	m.motd(w, nil)
+
+	found := false
+	for _, msg := range []string{"Holy cow!", "udder side.", "Moo."} {
+		found = found || strings.Contains(w.Body.String(), msg)
+	}
+	if !found {
+		t.Errorf("motd should use a fortune, got:\n%s", w.Body.String())
+	}
+}
+
 func Test_writeJSON(t *testing.T) {
 	w := httptest.NewRecorder()
 	response := map[string]string{"test": "data"}
@@ -171,6 +494,29 @@ func Test_writeJSON(t *testing.T) {
 	}
 }
 
+func TestNewModule_FontDir(t *testing.T) {
+	dir := t.TempDir()
+	font, err := os.ReadFile("../figlet/fonts/mini.flf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "tiny.flf"), font, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GOWSAY_FONT_DIR", dir)
+
+	m := NewModule()
+	if f, ok := m.font("tiny"); !ok || f.Name != "tiny" {
+		t.Errorf("font(tiny) = %v, %v", f, ok)
+	}
+	if f, ok := m.font(""); !ok || f.Name != figlet.DefaultFont {
+		t.Errorf("font() = %v, %v, want the default font", f, ok)
+	}
+	if names := m.fontNames(); !slices.Contains(names, "tiny") || !slices.Contains(names, "block") {
+		t.Errorf("fontNames() = %v", names)
+	}
+}
+
 func Test_sanitize(t *testing.T) {
 	tests := []struct {
 		name  string
@@ -199,13 +545,36 @@ func Test_sanitize(t *testing.T) {
 	}
 }
 
+func Test_rawTail(t *testing.T) {
+	tests := []struct {
+		text string
+		n    int
+		want string
+	}{
+		{"tux dead hi  there", 2, "hi  there"},
+		{" tux   ```\n  x\n```", 1, "```\n  x\n```"},
+		{"hi", 0, "hi"},
+		{"hi", 1, ""},
+	}
+
+	for _, tt := range tests {
+		if got := rawTail(tt.text, tt.n); got != tt.want {
+			t.Errorf("rawTail(%q, %d) = %q, want %q", tt.text, tt.n, got, tt.want)
+		}
+	}
+}
+
 func Test_NewModuleDefaults(t *testing.T) {
 	tests := []struct {
 		name        string
 		tokenEnv    string
 		columnsEnv  string
+		safeEnv     string
+		weightsEnv  string
 		wantToken   string
 		wantColumns int
+		wantSafe    bool
+		wantWeights int
 	}{
 		{
 			name:        "defaults",
@@ -214,6 +583,26 @@ func Test_NewModuleDefaults(t *testing.T) {
 			wantToken:   "devel",
 			wantColumns: 40,
 		},
+		{
+			name:        "safe-mode",
+			safeEnv:     "true",
+			wantToken:   "devel",
+			wantColumns: 40,
+			wantSafe:    true,
+		},
+		{
+			name:        "weights",
+			weightsEnv:  "tux=5,dragon=3",
+			wantToken:   "devel",
+			wantColumns: 40,
+			wantWeights: 2,
+		},
+		{
+			name:        "invalid-weights",
+			weightsEnv:  "tux",
+			wantToken:   "devel",
+			wantColumns: 40,
+		},
 		{
 			name:        "custom-values",
 			tokenEnv:    "test-token",
@@ -238,6 +627,12 @@ func Test_NewModuleDefaults(t *testing.T) {
 			if tt.columnsEnv != "" {
 				t.Setenv("GOWSAY_COLUMNS", tt.columnsEnv)
 			}
+			if tt.safeEnv != "" {
+				t.Setenv("GOWSAY_SAFE_MODE", tt.safeEnv)
+			}
+			if tt.weightsEnv != "" {
+				t.Setenv("GOWSAY_WEIGHTS", tt.weightsEnv)
+			}
 
 			got := NewModule()
 			if got.token != tt.wantToken {
@@ -246,6 +641,12 @@ func Test_NewModuleDefaults(t *testing.T) {
 			if got.columns != tt.wantColumns {
 				t.Errorf("NewModule().columns = %v, want %v", got.columns, tt.wantColumns)
 			}
+			if got.selector.SafeMode != tt.wantSafe {
+				t.Errorf("NewModule().selector.SafeMode = %v, want %v", got.selector.SafeMode, tt.wantSafe)
+			}
+			if len(got.selector.Weights) != tt.wantWeights {
+				t.Errorf("NewModule().selector.Weights = %v, want %d entries", got.selector.Weights, tt.wantWeights)
+			}
 		})
 	}
 }
@@ -1,5 +1,12 @@
 package api
 
+import (
+	"time"
+
+	"github.com/vnykmshr/gowsay/cow"
+	"github.com/vnykmshr/gowsay/figlet"
+)
+
 // Configuration and environment constants
 const (
 	envKey            = "ENV"
@@ -9,8 +16,19 @@ const (
 
 // Form field names
 const (
-	fieldToken = "token"
-	fieldText  = "text"
+	fieldToken       = "token"
+	fieldText        = "text"
+	fieldUserID      = "user_id"
+	fieldChannelID   = "channel_id"
+	fieldUserName    = "user_name"
+	fieldChannelName = "channel_name"
+	fieldTeamID      = "team_id"
+)
+
+// No-repeat scopes
+const (
+	scopeChannel = "channel"
+	scopeUser    = "user"
 )
 
 // Command keywords
@@ -19,8 +37,16 @@ const (
 	commandList     = "list"
 	commandSurprise = "surprise"
 	commandRandom   = "random"
+	commandFilter   = "filter"
+	commandBanner   = "banner"
+	commandCow      = "cow"
+	commandWear     = "wear"
+	commandTemplate = "template"
 )
 
+// codeFence opens and closes code blocks in messages
+const codeFence = "```"
+
 // Slack response types
 const (
 	responseEphemeral = "ephemeral"
@@ -32,10 +58,40 @@ const (
 	defaultCow = "default"
 )
 
+// Image formats, and HTML, which are served as is rather than in JSON, by
+// format name and file extension
+const (
+	imageSVG  = "svg"
+	imagePNG  = "png"
+	imageGIF  = "gif"
+	imageHTML = "html"
+)
+
+// imageContentTypes are the media types of the image formats
+var imageContentTypes = map[string]string{
+	imageSVG:  "image/svg+xml",
+	imagePNG:  "image/png",
+	imageGIF:  "image/gif",
+	imageHTML: "text/html; charset=utf-8",
+}
+
+// maxImageText is the longest text, in bytes, drawn as an SVG, PNG or GIF
+// image
+const maxImageText = 4096
+
 // Module holds handler dependencies
 type Module struct {
-	token   string
-	columns int
+	token        string
+	columns      int
+	selector     cow.Selector
+	repeatScope  string
+	location     *time.Location
+	now          func() time.Time
+	templateEnv  []string
+	templateDir  string
+	locale       string
+	slackLocales map[string]string
+	fonts        map[string]*figlet.Font
 }
 
 // SlackResponse represents a Slack-compatible response
@@ -3,78 +3,225 @@ package main
 import (
 	"bufio"
 	"context"
+	"errors"
 	"flag"
 	"fmt"
+	"image/color"
 	"io"
 	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
+	"os/user"
+	"path/filepath"
 	"sort"
+	"strconv"
+	"strings"
 	"syscall"
+	"text/tabwriter"
 	"time"
+	_ "time/tzdata" // time zones for the daily cow, even on hosts without zoneinfo
 
 	"github.com/vnykmshr/gowsay/api"
+	"github.com/vnykmshr/gowsay/canvas"
 	"github.com/vnykmshr/gowsay/cow"
+	"github.com/vnykmshr/gowsay/figlet"
+	"github.com/vnykmshr/gowsay/fortune"
+	"github.com/vnykmshr/gowsay/locale"
+	"github.com/vnykmshr/gowsay/message"
+	"github.com/vnykmshr/gowsay/qr"
 )
 
 var version = "devel"
 
+// Image output formats for -format
+const (
+	formatSVG  = "svg"
+	formatPNG  = "png"
+	formatGIF  = "gif"
+	formatHTML = "html"
+)
+
 func main() {
-	// Check if first argument is "serve"
-	if len(os.Args) > 1 && os.Args[1] == "serve" {
-		runServer()
-		return
+	// Check if first argument is a subcommand
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "serve":
+			runServer()
+			return
+		case "daily":
+			runSubcommand(runDaily)
+			return
+		case "fortune":
+			runSubcommand(runFortune)
+			return
+		case "cowfile":
+			runSubcommand(runCowfile)
+			return
+		}
 	}
 
 	// Run CLI mode
 	runCLI()
 }
 
+// runSubcommand runs a subcommand with the remaining arguments, exiting
+// non-zero if it fails
+func runSubcommand(run func(args []string, w io.Writer) error) {
+	err := run(os.Args[2:], os.Stdout)
+	if errors.Is(err, flag.ErrHelp) {
+		os.Exit(0)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
 func runCLI() {
 	// Define CLI flags
 	var (
-		cowName = flag.String("c", "default", "Cow name to use")
+		cowName = flag.String("c", "default", "Cow name to use, or random[:tag]")
 		mood    = flag.String("m", "", "Mood (borg, dead, greedy, paranoid, stoned, wired, young)")
 		think   = flag.Bool("t", false, "Think instead of say")
-		list    = flag.Bool("l", false, "List available cows and moods")
+		list    = flag.Bool("l", false, "List available cows and moods, or search cows: -l [query]")
+		long    = flag.Bool("long", false, "With -l, show cow descriptions, tags, size and rating")
+		tag     = flag.String("tag", "", "With -l, only list cows with this tag (animal, sci-fi, tech, holiday, ...)")
 		random  = flag.Bool("r", false, "Random cow and mood")
 		columns = flag.Int("w", 40, "Column width for text wrapping")
 		showVer = flag.Bool("v", false, "Show version")
+		safe    = flag.Bool("safe", envBool("GOWSAY_SAFE_MODE"), "Safe mode: hide cows and moods not rated safe")
+		seed    = flag.Int64("seed", 0, "Seed for random choices, to reproduce a previous output")
+		prSeed  = flag.Bool("print-seed", false, "Print the seed of a random output to stderr, even when stderr is not a terminal")
+		weights = flag.String("weights", os.Getenv("GOWSAY_WEIGHTS"), "Random pick weights for favourite cows and moods, e.g. tux=5,dragon=3")
+		exclude = flag.String("exclude", os.Getenv("GOWSAY_EXCLUDE"), "Cows and moods never picked at random, e.g. cheese,eyes")
+		packs   = flag.String("fortunes", os.Getenv("GOWSAY_FORTUNE_PATH"), "Fortune files or directories (colon-separated) for random messages when no message is given")
+		filter  = flag.String("filter", "", "Comma-separated text filters applied in order, e.g. upper,moo (see -l)")
+		tmpl    = flag.Bool("template", false, "Expand {{...}} template variables in the message; implied by -var")
+		banner  = flag.Bool("banner", false, "Draw the message as a FIGlet banner")
+		font    = flag.String("font", "", "Banner font: a built-in font (see -l) or a .flf file; implies -banner")
+		qrCode  = flag.Bool("qr", false, "Draw the message as a QR code")
+		qrLevel = flag.String("qr-level", qr.Medium.String(), "QR error correction level: L, M, Q or H")
+		qrQuiet = flag.Int("qr-quiet", qr.DefaultQuietZone, "QR quiet zone width in modules (0 to 16)")
+		qrInv   = flag.Bool("qr-invert", false, "Draw QR codes for light text on a dark background")
+		format  = flag.String("format", string(cow.FormatText), "Output format: text, ansi (highlighted code blocks), html, svg, png or gif")
+		outFile = flag.String("o", "", "Write the output to a file instead of stdout")
+		scale   = flag.Int("scale", canvas.DefaultScale, "Pixel scale of the bitmap font for PNG output")
+		family  = flag.String("font-family", canvas.DefaultFontFamily, "Font family for image output")
+		size    = flag.Int("font-size", canvas.DefaultFontSize, "Font size in pixels for image output")
+		padding = flag.Int("padding", canvas.DefaultPadding, "Padding in pixels around image output")
+		fg      = flag.String("fg", "", "Text color for image output, e.g. #24292f")
+		bg      = flag.String("bg", "", "Background color for image output, e.g. #ffffff or transparent")
+		animate = flag.String("animate", "", "Comma-separated animation effects: blink, type, wag; plays in the terminal or as -format gif")
+		loops   = flag.Int("loop", 1, "Times to play an animation, 0 for forever")
+		ink     = flag.String("color", "", "Color of the cow (red, bright-blue, gray, ...; see -l); implies -format ansi for text")
+		theme   = flag.String("theme", "", "Color theme for image and HTML output: light or dark")
+		page    = flag.Bool("page", false, "With -format html, write a standalone HTML page with a copy button")
+		gfx     = flag.String("graphics", "", "Show the cow as an inline image: sixel, kitty, or auto to detect the terminal")
+		wear    = flag.String("wear", "", "Comma-separated accessories for the cow (santa-hat, sunglasses, ...; see -l)")
+		vars    []string
 	)
+	flag.Func("var", "Template variable for the message, name=value (repeatable)", func(s string) error {
+		vars = append(vars, s)
+		return nil
+	})
 
 	flag.Usage = func() {
 		fmt.Fprintf(os.Stderr, "gowsay %s - cowsay implementation in Go\n\n", version)
 		fmt.Fprintf(os.Stderr, "Usage:\n")
 		fmt.Fprintf(os.Stderr, "  gowsay [options] [message...]\n")
 		fmt.Fprintf(os.Stderr, "  echo \"message\" | gowsay [options]\n")
-		fmt.Fprintf(os.Stderr, "  gowsay serve                    Start HTTP server\n\n")
+		fmt.Fprintf(os.Stderr, "  gowsay serve                    Start HTTP server\n")
+		fmt.Fprintf(os.Stderr, "  gowsay daily [options]          Cow of the day\n")
+		fmt.Fprintf(os.Stderr, "  gowsay fortune [options]        Random fortune from a cow\n")
+		fmt.Fprintf(os.Stderr, "  gowsay cowfile from-image <img> Convert an image into a cowfile\n")
+		fmt.Fprintf(os.Stderr, "  gowsay cowfile lint <file>...   Check cowfiles, with a JSON report\n")
+		fmt.Fprintf(os.Stderr, "  gowsay cowfile export <name>... | --all\n")
+		fmt.Fprintf(os.Stderr, "                                  Export cows as classic Perl cowfiles\n")
+		fmt.Fprintf(os.Stderr, "  gowsay cowfile import <file>    Convert a classic Perl cowfile into a template\n\n")
 		fmt.Fprintf(os.Stderr, "Options:\n")
 		flag.PrintDefaults()
 	}
 
 	flag.Parse()
 
+	seeded := false
+	flag.Visit(func(f *flag.Flag) { seeded = seeded || f.Name == "seed" })
+	if !seeded {
+		*seed = cow.NewSeed()
+	}
+	rng := cow.NewRand(*seed)
+	sel := cow.Selector{SafeMode: *safe, Rand: rng, Exclude: cow.ParseList(*exclude)}
+	if w, err := cow.ParseWeights(*weights); err == nil {
+		sel.Weights = w
+	} else {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
 	// Show version
 	if *showVer {
 		fmt.Printf("gowsay %s\n", version)
 		os.Exit(0)
 	}
 
+	// Search cows
+	if *list && (flag.NArg() > 0 || *tag != "") {
+		matches := sel.Search(strings.Join(flag.Args(), " "), *tag)
+		if len(matches) == 0 {
+			fmt.Fprintln(os.Stderr, "No matching cows")
+			os.Exit(1)
+		}
+		infos := make([]cow.Info, len(matches))
+		for i, match := range matches {
+			infos[i] = match.Info
+		}
+		if *long {
+			printCowInfo(os.Stdout, infos)
+		} else {
+			for _, info := range infos {
+				fmt.Println(info.Name)
+			}
+		}
+		os.Exit(0)
+	}
+
 	// List cows and moods
 	if *list {
-		cows := cow.List()
-		sort.Strings(cows)
 		fmt.Println("Available cows:")
-		for _, c := range cows {
-			fmt.Printf("  %s\n", c)
+		if *long {
+			infos := sel.ListInfo()
+			sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+			printCowInfo(os.Stdout, infos)
+		} else {
+			cows := sel.List()
+			sort.Strings(cows)
+			for _, c := range cows {
+				fmt.Printf("  %s\n", c)
+			}
 		}
 		fmt.Println("\nAvailable moods:")
-		moods := cow.ListMoods()
+		moods := sel.ListMoods()
 		sort.Strings(moods)
 		for _, m := range moods {
 			fmt.Printf("  %s\n", m)
 		}
+		fmt.Println("\nAvailable filters:")
+		for _, f := range cow.ListFilters() {
+			fmt.Printf("  %s\n", f)
+		}
+		fmt.Println("\nAvailable fonts:")
+		for _, f := range figlet.Builtins() {
+			fmt.Printf("  %s\n", f)
+		}
+		fmt.Println("\nAvailable colors:")
+		for _, c := range cow.Colors() {
+			fmt.Printf("  %s\n", c)
+		}
+		fmt.Println("\nAvailable accessories:")
+		for _, a := range cow.Accessories() {
+			fmt.Printf("  %s\n", a)
+		}
 		os.Exit(0)
 	}
 
@@ -82,36 +229,129 @@ func runCLI() {
 	var text []string
 	args := flag.Args()
 
+	separator := "\n"
 	if len(args) > 0 {
 		// Use command line arguments
 		text = args
+		separator = " "
 	} else {
 		// Read from stdin
 		text = readStdin()
-		if len(text) == 0 {
-			flag.Usage()
+	}
+
+	p := locale.NewPrinter(locale.FromEnv())
+
+	// Expand template variables in the message, only when asked for so
+	// text with braces, such as Helm or Jinja snippets, is said as is
+	if len(text) > 0 && (*tmpl || len(vars) > 0) {
+		var err error
+		if text, err = messageTemplate(vars).ExpandLines(text); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", p.Error(err))
 			os.Exit(1)
 		}
 	}
 
-	// Apply random if requested
-	if *random {
-		*cowName = cow.RandomCow()
-		*mood = cow.RandomMood()
+	// Fall back to a random fortune. Packs are only loaded when one is
+	// needed, so a bad path does not break -v or -l.
+	if len(text) == 0 && *packs != "" {
+		loaded, err := fortune.LoadPath(*packs)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: loading fortunes: %v\n", err)
+			os.Exit(1)
+		}
+		sel.Messages = fortune.Messages(loaded)
+	}
+	if len(text) == 0 && len(sel.Messages) > 0 {
+		text = []string{sel.RandomMessage()}
+	}
+	if len(text) == 0 {
+		flag.Usage()
+		os.Exit(1)
 	}
 
-	// Validate cow exists
-	if !cow.Exists(*cowName) {
-		fmt.Fprintf(os.Stderr, "Error: cow '%s' not found\n", *cowName)
+	// Resolve random picks, among cows that can wear the accessories, and
+	// validate the cow and mood
+	accessories, err := cow.ParseAccessories(*wear)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", p.Error(err))
+		os.Exit(1)
+	}
+	sel.Accessories = accessories
+	if *cowName, *mood, err = chooseCow(sel, p, *cowName, *mood, *random); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if err := cow.CheckAccessories(*cowName, accessories); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", p.Error(err))
 		os.Exit(1)
 	}
 
-	// Validate mood if specified
-	if *mood != "" && !cow.MoodExists(*mood) {
-		fmt.Fprintf(os.Stderr, "Error: mood '%s' not found\n", *mood)
+	filters, err := cow.ParseFilters(*filter)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", p.Error(err))
+		os.Exit(1)
+	}
+
+	effects, err := cow.ParseEffects(*animate)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", p.Error(err))
 		os.Exit(1)
 	}
 
+	// Images are drawn from ANSI output, keeping its colors and styles
+	imageType := strings.ToLower(*format)
+	outFormat := cow.FormatANSI
+	if imageType == formatGIF && len(effects) == 0 {
+		effects = []string{cow.EffectBlink, cow.EffectWag}
+	}
+	if imageType != formatSVG && imageType != formatPNG && imageType != formatGIF && imageType != formatHTML {
+		imageType = ""
+		if outFormat, err = cow.ParseFormat(*format); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", p.Sprintf("format '%s' not found", *format))
+			os.Exit(1)
+		}
+	}
+	if *ink != "" && !cow.ColorExists(*ink) {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", p.Sprintf("cow color '%s' not found", *ink))
+		os.Exit(1)
+	}
+	if *ink != "" && outFormat == cow.FormatText {
+		outFormat = cow.FormatANSI
+	}
+	imageOpts, err := imageOptions(p, *family, *size, *scale, *padding, *theme, *fg, *bg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", p.Error(err))
+		os.Exit(1)
+	}
+	graphics, err := graphicsProtocol(p, *gfx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if graphics != "" {
+		imageType, outFormat = graphics, cow.FormatANSI
+	}
+	if err := checkAnimate(p, *animate, imageType, *outFile); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	var bannerFont *figlet.Font
+	if *banner || *font != "" {
+		if bannerFont, err = loadFont(p, *font); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	var code *qr.Code
+	if *qrCode {
+		if code, err = encodeQR(strings.Join(text, separator), *qrLevel, *qrQuiet, *qrInv); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", p.Error(err))
+			os.Exit(1)
+		}
+	}
+
 	// Determine action
 	action := cow.ActionSay
 	if *think {
@@ -119,8 +359,241 @@ func runCLI() {
 	}
 
 	// Render and output
-	output := cow.Render(text, *cowName, *mood, action, *columns)
-	fmt.Print(output)
+	renderer := &cow.Renderer{Rand: rng, Filters: filters, Font: bannerFont, QR: code, Format: outFormat, Color: *ink, Accessories: accessories}
+	// Animations play in a terminal; piped output gets the final frame,
+	// which is the still cow
+	if len(effects) > 0 && imageType == "" && *outFile == "" && isTerminal(os.Stdout) {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+		frames := renderer.Animate(effects, text, *cowName, *mood, action, *columns)
+		if err := cow.Play(ctx, os.Stdout, frames, *loops); err != nil && !errors.Is(err, context.Canceled) {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	output := renderer.Render(text, *cowName, *mood, action, *columns)
+	data := []byte(output)
+	switch imageType {
+	case formatGIF:
+		frames := renderer.Animate(effects, text, *cowName, *mood, action, *columns)
+		if data, err = canvas.GIF(frames, *loops, imageOpts); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", p.Error(err))
+			os.Exit(1)
+		}
+	case formatHTML:
+		data = htmlOutput(output, imageOpts, *theme != "", *page)
+	case formatSVG:
+		data = canvas.SVG(output, imageOpts)
+	case canvas.GraphicsSixel:
+		if data, err = canvas.Sixel(output, imageOpts); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", p.Error(err))
+			os.Exit(1)
+		}
+	case canvas.GraphicsKitty:
+		if data, err = canvas.Kitty(output, imageOpts); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", p.Error(err))
+			os.Exit(1)
+		}
+	case formatPNG:
+		if data, err = canvas.PNG(output, imageOpts); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", p.Error(err))
+			os.Exit(1)
+		}
+	}
+	if *outFile != "" {
+		err = os.WriteFile(*outFile, data, 0o644)
+	} else {
+		_, err = os.Stdout.Write(data)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Report the seed so a random output can be reproduced with -seed,
+	// keeping it out of scripts and logs unless asked for
+	if rng.Used() && (*prSeed || !seeded && isTerminal(os.Stderr)) {
+		fmt.Fprintf(os.Stderr, "seed: %d\n", rng.Seed())
+	}
+}
+
+// chooseCow resolves random cow and mood picks ("random", "random:<tag>",
+// or everything random with all set) and checks that the selector allows
+// the result. Random cows can wear the selector's accessories. Errors are
+// in the printer's language.
+func chooseCow(sel cow.Selector, p *locale.Printer, cowName, mood string, all bool) (string, string, error) {
+	if all {
+		cowName = sel.RandomCow()
+		mood = sel.RandomMood()
+	} else if tag, ok := cow.ParseRandom(cowName); ok {
+		name, found := sel.RandomTagged(tag)
+		if !found && len(sel.Accessories) > 0 {
+			return "", "", errors.New(p.Sprintf("no cows tagged '%s' can wear %s", tag, strings.Join(sel.Accessories, ", ")))
+		}
+		if !found {
+			return "", "", errors.New(p.Sprintf("no cows tagged '%s'", tag))
+		}
+		cowName = name
+	}
+	if mood == "random" {
+		mood = sel.RandomMood()
+	}
+
+	if !cow.Exists(cowName) {
+		if suggestion, ok := sel.Suggest(cowName); ok {
+			return "", "", errors.New(p.Sprintf("cow '%s' not found, did you mean '%s'?", cowName, suggestion))
+		}
+		return "", "", errors.New(p.Sprintf("cow '%s' not found", cowName))
+	}
+	if !sel.CowAllowed(cowName) {
+		return "", "", errors.New(p.Sprintf("cow '%s' is not available in safe mode", cowName))
+	}
+	if mood != "" && !cow.MoodExists(mood) {
+		if suggestion, ok := sel.SuggestMood(mood); ok {
+			return "", "", errors.New(p.Sprintf("mood '%s' not found, did you mean '%s'?", mood, suggestion))
+		}
+		return "", "", errors.New(p.Sprintf("mood '%s' not found", mood))
+	}
+	if mood != "" && !sel.MoodAllowed(mood) {
+		return "", "", errors.New(p.Sprintf("mood '%s' is not available in safe mode", mood))
+	}
+	return cowName, mood, nil
+}
+
+// loadFont returns the banner font: the default font if name is empty, a
+// font file if name looks like a path, or else a built-in font
+func loadFont(p *locale.Printer, name string) (*figlet.Font, error) {
+	if name == "" {
+		name = figlet.DefaultFont
+	}
+	if strings.HasSuffix(name, ".flf") || name != filepath.Base(name) {
+		return figlet.Load(name)
+	}
+	f, ok := figlet.Builtin(name)
+	if !ok {
+		return nil, errors.New(p.Sprintf("font '%s' not found", name))
+	}
+	return f, nil
+}
+
+// encodeQR encodes text as a QR code with the given level letter, quiet
+// zone and colors
+func encodeQR(text, level string, quiet int, invert bool) (*qr.Code, error) {
+	l, err := qr.ParseLevel(level)
+	if err != nil {
+		return nil, err
+	}
+	return qr.Encode(text, qr.Options{Level: l, QuietZone: quiet, Invert: invert})
+}
+
+// htmlOutput returns rendered ANSI text as an HTML <pre> block, styled
+// inline with opts when themed, or as a standalone page
+func htmlOutput(output string, opts canvas.Options, themed, page bool) []byte {
+	if page {
+		return canvas.Page(output, opts)
+	}
+	if !themed {
+		opts = canvas.Options{}
+	}
+	return canvas.HTML(output, opts)
+}
+
+// graphicsProtocol returns the terminal graphics protocol named by the
+// -graphics flag. With "auto", it is detected from the environment when
+// stdout is a terminal, or "" for text output if it cannot be.
+func graphicsProtocol(p *locale.Printer, name string) (string, error) {
+	switch strings.ToLower(name) {
+	case "":
+		return "", nil
+	case canvas.GraphicsSixel:
+		return canvas.GraphicsSixel, nil
+	case canvas.GraphicsKitty:
+		return canvas.GraphicsKitty, nil
+	case "auto":
+		if !isTerminal(os.Stdout) {
+			return "", nil
+		}
+		return canvas.DetectGraphics(os.Getenv), nil
+	}
+	return "", errors.New(p.Sprintf("graphics '%s' must be sixel, kitty or auto", name))
+}
+
+// checkAnimate rejects -animate for output that cannot move: animations
+// play in a terminal or are written as GIFs
+func checkAnimate(p *locale.Printer, animate, imageType, outFile string) error {
+	if animate != "" && imageType != formatGIF && (imageType != "" || outFile != "") {
+		return errors.New(p.Sprintf("-animate plays in a terminal or needs -format gif"))
+	}
+	return nil
+}
+
+// imageOptions builds image output options from flag values. Empty colors
+// keep the defaults.
+func imageOptions(p *locale.Printer, family string, size, scale, padding int, theme, fg, bg string) (canvas.Options, error) {
+	opts := canvas.DefaultOptions()
+	if theme != "" && !opts.SetTheme(theme) {
+		return opts, errors.New(p.Sprintf("theme '%s' not found", theme))
+	}
+	opts.FontFamily, opts.FontSize, opts.Scale, opts.Padding = family, size, scale, padding
+	for _, c := range []struct {
+		value string
+		dst   *color.NRGBA
+	}{{fg, &opts.Foreground}, {bg, &opts.Background}} {
+		if c.value == "" {
+			continue
+		}
+		rgba, err := canvas.ParseColor(c.value)
+		if err != nil {
+			return opts, err
+		}
+		*c.dst = rgba
+	}
+	return opts, opts.Validate()
+}
+
+// messageTemplate returns the template for CLI messages, with the given
+// name=value variables and the current user
+func messageTemplate(pairs []string) message.Template {
+	vars, err := message.ParseVars(pairs)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if _, ok := vars[message.VarUser]; !ok {
+		if u, err := user.Current(); err == nil {
+			vars[message.VarUser] = u.Username
+		}
+	}
+	return message.Template{
+		Vars: vars,
+		Now:  time.Now(),
+		Env:  cow.ParseList(os.Getenv("GOWSAY_TEMPLATE_ENV")),
+		Dir:  os.Getenv("GOWSAY_TEMPLATE_DIR"),
+	}
+}
+
+// printCowInfo writes one line of metadata per cow
+func printCowInfo(w io.Writer, infos []cow.Info) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, info := range infos {
+		fmt.Fprintf(tw, "  %s\t%dx%d\t%s\t%s\t%s\n",
+			info.Name, info.Width, info.Height, info.Rating, strings.Join(info.Tags, ","), info.Description)
+	}
+	tw.Flush()
+}
+
+// envBool reads a boolean environment variable, treating unset or invalid values as false
+func envBool(key string) bool {
+	v, _ := strconv.ParseBool(os.Getenv(key))
+	return v
+}
+
+// isTerminal reports whether f is a terminal
+func isTerminal(f *os.File) bool {
+	stat, err := f.Stat()
+	return err == nil && stat.Mode()&os.ModeCharDevice != 0
 }
 
 func readStdin() []string {
@@ -160,16 +633,21 @@ func runServer() {
 
 	// New API endpoints (with CORS)
 	http.HandleFunc("/api/moo", api.CORS(m.APIMoo))
+	http.HandleFunc("/api/moo.svg", api.CORS(m.APIMoo))
+	http.HandleFunc("/api/moo.png", api.CORS(m.APIMoo))
+	http.HandleFunc("/api/moo.gif", api.CORS(m.APIMoo))
+	http.HandleFunc("/api/moo.html", api.CORS(m.APIMoo))
 	http.HandleFunc("/api/cows", api.CORS(m.APICows))
 	http.HandleFunc("/api/moods", api.CORS(m.APIMoods))
+	http.HandleFunc("/api/daily", api.CORS(m.APIDaily))
 	http.HandleFunc("/health", api.CORS(api.Health(version)))
 
 	// Web UI - serve at root
 	http.Handle("/", api.ServeWeb())
 
-	fmt.Println(api.GetBanner(version))
+	fmt.Println(api.GetBanner(version, m.Selector()))
 	slog.Info("routes registered",
-		"endpoints", []string{"/", "/say", "/api/moo", "/api/cows", "/api/moods", "/health"})
+		"endpoints", []string{"/", "/say", "/api/moo", "/api/moo.svg", "/api/moo.png", "/api/moo.gif", "/api/moo.html", "/api/cows", "/api/moods", "/api/daily", "/health"})
 
 	// Get port from environment or use default
 	port := os.Getenv("PORT")
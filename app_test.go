@@ -3,11 +3,17 @@ package main
 import (
 	"bytes"
 	"flag"
+	"image/color"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
+	"github.com/vnykmshr/gowsay/canvas"
 	"github.com/vnykmshr/gowsay/cow"
+	"github.com/vnykmshr/gowsay/figlet"
+	"github.com/vnykmshr/gowsay/locale"
+	"github.com/vnykmshr/gowsay/qr"
 )
 
 // TestRenderCLI tests the core CLI rendering logic without os.Exit
@@ -370,3 +376,180 @@ func BenchmarkCLIRenderLongText(b *testing.B) {
 		_ = cow.Render(text, "default", "", cow.ActionSay, 40)
 	}
 }
+
+func TestChooseCow_Accessories(t *testing.T) {
+	sel := cow.Selector{Rand: cow.NewRand(1), Accessories: []string{"sunglasses", "santa-hat"}}
+	for i := 0; i < 20; i++ {
+		for _, name := range []string{"random", "random:animal"} {
+			got, _, err := chooseCow(sel, nil, name, "", false)
+			if err != nil {
+				t.Fatalf("chooseCow(%s) error = %v", name, err)
+			}
+			if err := cow.CheckAccessories(got, sel.Accessories); err != nil {
+				t.Fatalf("chooseCow(%s) = %s, which cannot wear them: %v", name, got, err)
+			}
+		}
+		if got, _, _ := chooseCow(sel, nil, "default", "", true); cow.CheckAccessories(got, sel.Accessories) != nil {
+			t.Fatalf("chooseCow(-r) = %s, which cannot wear them", got)
+		}
+	}
+
+	sel.Accessories = []string{"mug"}
+	want := "no cows tagged 'holiday' can wear mug"
+	if _, _, err := chooseCow(sel, nil, "random:holiday", "", false); err == nil || err.Error() != want {
+		t.Errorf("chooseCow(random:holiday) error = %v, want %q", err, want)
+	}
+}
+
+func TestLoadFont(t *testing.T) {
+	tests := []struct {
+		name    string
+		font    string
+		want    string
+		wantErr bool
+	}{
+		{"default", "", figlet.DefaultFont, false},
+		{"built-in", "block", "block", false},
+		{"file", filepath.Join("figlet", "fonts", "mini.flf"), "mini", false},
+		{"unknown", "nope", "", true},
+		{"missing file", filepath.Join(t.TempDir(), "nope.flf"), "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := loadFont(nil, tt.font)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("loadFont(%q) error = %v, wantErr %v", tt.font, err, tt.wantErr)
+			}
+			if err == nil && f.Name != tt.want {
+				t.Errorf("loadFont(%q) = %s, want %s", tt.font, f.Name, tt.want)
+			}
+		})
+	}
+}
+
+func TestEncodeQR(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		level   string
+		quiet   int
+		wantErr string
+	}{
+		{"default", "https://example.com", "M", qr.DefaultQuietZone, ""},
+		{"lower case level", "moo", "h", 0, ""},
+		{"bad level", "moo", "X", 0, "QR level must be L, M, Q or H"},
+		{"negative quiet zone", "moo", "L", -1, "QR quiet zone must be between 0 and 16"},
+		{"too long", strings.Repeat("moo", 1000), "L", 0, "text is too long for a QR code"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, err := encodeQR(tt.text, tt.level, tt.quiet, false)
+			if tt.wantErr != "" {
+				if err == nil || (*locale.Printer)(nil).Error(err) != tt.wantErr {
+					t.Errorf("encodeQR() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("encodeQR() error = %v", err)
+			}
+			if lines := code.Lines(); len([]rune(lines[0])) != code.Size()+2*tt.quiet {
+				t.Errorf("encodeQR() first line %q, want %d columns", lines[0], code.Size()+2*tt.quiet)
+			}
+		})
+	}
+}
+
+func TestImageOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		family  string
+		size    int
+		scale   int
+		padding int
+		theme   string
+		fg, bg  string
+		wantErr string
+	}{
+		{"defaults", "monospace", canvas.DefaultFontSize, canvas.DefaultScale, canvas.DefaultPadding, "", "", "", ""},
+		{"colors", "monospace", 20, 1, 0, "", "#fff", "transparent", ""},
+		{"theme with colors", "monospace", 20, 1, 0, "dark", "#fff", "transparent", ""},
+		{"font too small", "monospace", 1, 1, 0, "", "", "", "font size must be between 4 and 96"},
+		{"scale too large", "monospace", 14, 9, 0, "", "", "", "scale must be between 1 and 8"},
+		{"negative padding", "monospace", 14, 1, -1, "", "", "", "padding must be between 0 and 256"},
+		{"bad color", "monospace", 14, 1, 0, "", "", "blue", "color 'blue'"},
+		{"bad font family", "x;background:red", 14, 1, 0, "", "", "", "font family 'x;background:red'"},
+		{"bad theme", "monospace", 14, 1, 0, "sepia", "", "", "theme 'sepia' not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts, err := imageOptions(nil, tt.family, tt.size, tt.scale, tt.padding, tt.theme, tt.fg, tt.bg)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("imageOptions() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("imageOptions() error = %v", err)
+			}
+			if opts.FontSize != tt.size || opts.Scale != tt.scale || opts.Padding != tt.padding || opts.FontFamily != tt.family {
+				t.Errorf("imageOptions() = %+v", opts)
+			}
+			if tt.theme == "" && tt.fg == "" && opts.Background != canvas.DefaultOptions().Background {
+				t.Errorf("imageOptions() background = %v, want the default", opts.Background)
+			}
+			if tt.bg == "transparent" && (opts.Background.A != 0 || opts.Foreground != (color.NRGBA{0xff, 0xff, 0xff, 0xff})) {
+				t.Errorf("imageOptions() colors = %v on %v", opts.Foreground, opts.Background)
+			}
+		})
+	}
+}
+
+func TestGraphicsProtocol(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    string
+		wantErr bool
+	}{
+		{"", "", false},
+		{"sixel", canvas.GraphicsSixel, false},
+		{"Kitty", canvas.GraphicsKitty, false},
+		{"iterm", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := graphicsProtocol(nil, tt.name)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("graphicsProtocol(%q) = %q, %v, want %q", tt.name, got, err, tt.want)
+		}
+	}
+}
+
+func TestCheckAnimate(t *testing.T) {
+	tests := []struct {
+		name      string
+		animate   string
+		imageType string
+		outFile   string
+		wantErr   bool
+	}{
+		{"terminal", "blink", "", "", false},
+		{"gif file", "type,wag", formatGIF, "cow.gif", false},
+		{"gif stdout", "blink", formatGIF, "", false},
+		{"no animation", "", formatPNG, "cow.png", false},
+		{"text file", "blink", "", "cow.txt", true},
+		{"png file", "blink", formatPNG, "cow.png", true},
+		{"svg stdout", "wag", formatSVG, "", true},
+		{"sixel", "blink", canvas.GraphicsSixel, "", true},
+	}
+
+	for _, tt := range tests {
+		if err := checkAnimate(nil, tt.animate, tt.imageType, tt.outFile); (err != nil) != tt.wantErr {
+			t.Errorf("%s: checkAnimate() error = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
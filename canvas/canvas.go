@@ -0,0 +1,229 @@
+package canvas
+
+import (
+	"fmt"
+	"image/color"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	runewidth "github.com/mattn/go-runewidth"
+	"github.com/vnykmshr/gowsay/ansi"
+)
+
+// Defaults for image output
+const (
+	DefaultFontFamily = "DejaVu Sans Mono, Menlo, Consolas, monospace"
+	DefaultFontSize   = 14
+	DefaultPadding    = 16
+	DefaultScale      = 2
+)
+
+// Limits on image options, so a request cannot ask for a huge image
+const (
+	MinFontSize = 4
+	MaxFontSize = 96
+	MaxPadding  = 256
+	MaxScale    = 8
+	MaxPixels   = 1 << 23 // in one PNG image or GIF frame
+
+	// MaxAnimationPixels is the most pixels in all the frames of a GIF
+	MaxAnimationPixels = 1 << 25
+)
+
+// Errors for images and animations over the pixel limits
+var (
+	ErrTooLarge          = fmt.Errorf("image is larger than %d pixels", MaxPixels)
+	ErrAnimationTooLarge = fmt.Errorf("animation is larger than %d pixels in all", MaxAnimationPixels)
+)
+
+// Options configure image output. Colors with zero alpha are transparent.
+// SVG text uses the font family and size; PNG images use the embedded
+// bitmap font, scaled.
+type Options struct {
+	FontFamily string
+	FontSize   int
+	Scale      int
+	Foreground color.NRGBA
+	Background color.NRGBA
+	Padding    int
+}
+
+// Names of the options in a RangeError
+const (
+	OptionFontSize = "font size"
+	OptionScale    = "scale"
+	OptionPadding  = "padding"
+)
+
+// RangeError is returned by Options.Validate for a number outside its
+// limits
+type RangeError struct {
+	Option string
+	Min    int
+	Max    int
+}
+
+func (e *RangeError) Error() string {
+	return fmt.Sprintf("%s must be between %d and %d", e.Option, e.Min, e.Max)
+}
+
+// FontFamilyError is returned by Options.Validate for a font family with
+// characters other than letters, digits, spaces, commas, hyphens and quotes
+type FontFamilyError struct {
+	Family string
+}
+
+func (e *FontFamilyError) Error() string {
+	return fmt.Sprintf("font family '%s' may only have letters, digits, spaces, commas, hyphens and quotes", e.Family)
+}
+
+// ColorError is returned by ParseColor for a color it cannot read
+type ColorError struct {
+	Color string
+}
+
+func (e *ColorError) Error() string {
+	return fmt.Sprintf("color '%s' must be #rgb, #rrggbb, #rrggbbaa or transparent", e.Color)
+}
+
+// Validate checks the options against the limits. The font family must
+// not be able to end the CSS declaration or SVG attribute it is written
+// into.
+func (o Options) Validate() error {
+	if !validFontFamily(o.FontFamily) {
+		return &FontFamilyError{Family: o.FontFamily}
+	}
+	for _, r := range []struct {
+		option        string
+		value, lo, hi int
+	}{
+		{OptionFontSize, o.FontSize, MinFontSize, MaxFontSize},
+		{OptionScale, o.Scale, 1, MaxScale},
+		{OptionPadding, o.Padding, 0, MaxPadding},
+	} {
+		if r.value < r.lo || r.value > r.hi {
+			return &RangeError{Option: r.option, Min: r.lo, Max: r.hi}
+		}
+	}
+	return nil
+}
+
+// DefaultOptions returns the light theme: dark text on a white background
+func DefaultOptions() Options {
+	return Options{
+		FontFamily: DefaultFontFamily,
+		FontSize:   DefaultFontSize,
+		Scale:      DefaultScale,
+		Foreground: themes["light"][0],
+		Background: themes["light"][1],
+		Padding:    DefaultPadding,
+	}
+}
+
+// themes are text and background colors by name
+var themes = map[string][2]color.NRGBA{
+	"light": {{0x24, 0x29, 0x2f, 0xff}, {0xff, 0xff, 0xff, 0xff}},
+	"dark":  {{0xe6, 0xed, 0xf3, 0xff}, {0x0d, 0x11, 0x17, 0xff}},
+}
+
+// Themes returns the names of the color themes
+func Themes() []string {
+	return slices.Sorted(maps.Keys(themes))
+}
+
+// SetTheme sets the text and background colors to those of a theme,
+// reporting whether it exists
+func (o *Options) SetTheme(name string) bool {
+	colors, ok := themes[strings.ToLower(name)]
+	if ok {
+		o.Foreground, o.Background = colors[0], colors[1]
+	}
+	return ok
+}
+
+// palette returns the colors images are drawn in: the background first,
+// so it is the transparent color if it has no alpha, then the foreground
+// and the terminal colors
+func (o Options) palette() color.Palette {
+	palette := color.Palette{o.Background, o.Foreground}
+	for _, code := range slices.Sorted(maps.Keys(ansi.Palette)) {
+		palette = append(palette, ansi.Palette[code])
+	}
+	return palette
+}
+
+// validFontFamily reports whether a font family list only has letters,
+// digits, spaces, commas, hyphens and quotes
+func validFontFamily(s string) bool {
+	for _, r := range s {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && !strings.ContainsRune(" ,-'\"", r) {
+			return false
+		}
+	}
+	return true
+}
+
+// ParseColor reads a color as #rgb, #rrggbb or #rrggbbaa hex, or the name
+// "transparent"
+func ParseColor(s string) (color.NRGBA, error) {
+	if strings.EqualFold(s, "transparent") || strings.EqualFold(s, "none") {
+		return color.NRGBA{}, nil
+	}
+	hex, ok := strings.CutPrefix(s, "#")
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+	if len(hex) == 6 {
+		hex += "ff"
+	}
+	n, err := strconv.ParseUint(hex, 16, 32)
+	if !ok || len(hex) != 8 || err != nil {
+		return color.NRGBA{}, &ColorError{Color: s}
+	}
+	return color.NRGBA{uint8(n >> 24), uint8(n >> 16), uint8(n >> 8), uint8(n)}, nil
+}
+
+// cell is one character cell of text: a character and its style. Wide
+// characters take two cells; the second has no text.
+type cell struct {
+	text  string
+	style ansi.Style
+}
+
+// grid lays text with SGR escape sequences out in character cells, one
+// row per line
+func grid(text string) [][]cell {
+	var rows [][]cell
+	for _, line := range ansi.Parse(strings.TrimSuffix(text, "\n")) {
+		var row []cell
+		for _, run := range line {
+			for _, r := range run.Text {
+				w := runewidth.RuneWidth(r)
+				if w == 0 {
+					if r == '\t' {
+						row = append(row, cell{text: " ", style: run.Style})
+					}
+					continue
+				}
+				row = append(row, cell{text: string(r), style: run.Style})
+				if w == 2 {
+					row = append(row, cell{style: run.Style})
+				}
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// columns returns the width of the widest row
+func columns(rows [][]cell) int {
+	width := 0
+	for _, row := range rows {
+		width = max(width, len(row))
+	}
+	return width
+}
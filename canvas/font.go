@@ -0,0 +1,146 @@
+package canvas
+
+import (
+	"bufio"
+	_ "embed"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Cell size of the bitmap font in pixels
+const (
+	glyphWidth  = 6
+	glyphHeight = 10
+)
+
+//go:embed font6x10.txt
+var fontFile string
+
+// bitmap is a glyph, one row of pixels per element with bit x set for ink
+// in column x. Wide characters use two cells, twelve columns.
+type bitmap [glyphHeight]uint16
+
+// glyphs parses the embedded font once
+var glyphs = sync.OnceValue(func() map[rune]bitmap {
+	font, err := parseFont(fontFile)
+	if err != nil {
+		panic(fmt.Sprintf("canvas: embedded font: %v", err))
+	}
+	return font
+})
+
+// parseFont reads a font in the format of font6x10.txt
+func parseFont(s string) (map[rune]bitmap, error) {
+	font := make(map[rune]bitmap)
+	scanner := bufio.NewScanner(strings.NewReader(s))
+	line := 0
+	for scanner.Scan() {
+		line++
+		header := scanner.Text()
+		if header == "" || strings.HasPrefix(header, "#") {
+			continue
+		}
+		code, _, _ := strings.Cut(header, " ")
+		r, err := strconv.ParseUint(strings.TrimPrefix(code, "U+"), 16, 32)
+		if err != nil || !strings.HasPrefix(code, "U+") {
+			return nil, fmt.Errorf("line %d: want a U+XXXX glyph header, got %q", line, header)
+		}
+
+		var b bitmap
+		for y := range b {
+			if !scanner.Scan() {
+				return nil, fmt.Errorf("line %d: %s has %d rows, want %d", line, code, y, glyphHeight)
+			}
+			line++
+			row := scanner.Text()
+			if len(row) != glyphWidth {
+				return nil, fmt.Errorf("line %d: %s row is %d pixels wide, want %d", line, code, len(row), glyphWidth)
+			}
+			for x := range glyphWidth {
+				if row[x] == '#' {
+					b[y] |= 1 << x
+				}
+			}
+		}
+		font[rune(r)] = b
+	}
+	return font, scanner.Err()
+}
+
+// boxArms are the lines of box drawing characters, as up, down, left and
+// right arms from the middle of the cell
+var boxArms = map[rune][4]bool{
+	'─': {false, false, true, true}, '│': {true, true, false, false},
+	'┌': {false, true, false, true}, '┐': {false, true, true, false},
+	'└': {true, false, false, true}, '┘': {true, false, true, false},
+	'├': {true, true, false, true}, '┤': {true, true, true, false},
+	'┬': {false, true, true, true}, '┴': {true, false, true, true},
+	'┼': {true, true, true, true},
+}
+
+// glyph returns the bitmap of a character taking the given number of
+// cells. Block elements and box drawing lines are drawn to fill the cell so
+// they join up; characters not in the font are an empty box.
+func glyph(r rune, cells int) bitmap {
+	const full = 1<<glyphWidth - 1
+	const left, right = 0b000111, 0b111000
+
+	if b, ok := glyphs()[r]; ok && cells == 1 {
+		return b
+	}
+
+	var b bitmap
+	switch r {
+	case '█', '▀', '▄', '▌', '▐':
+		for y := range b {
+			switch {
+			case r == '█', r == '▀' && y < glyphHeight/2, r == '▄' && y >= glyphHeight/2:
+				b[y] = full
+			case r == '▌':
+				b[y] = left
+			case r == '▐':
+				b[y] = right
+			}
+		}
+		return b
+	case '░', '▒', '▓':
+		for y := range b {
+			switch r {
+			case '░':
+				b[y] = 0b010001 << (y % 2 * 2) & full
+			case '▒':
+				b[y] = 0b010101 << (y % 2)
+			case '▓':
+				b[y] = full &^ (0b100010 >> (y % 2 * 2))
+			}
+		}
+		return b
+	}
+
+	if arms, ok := boxArms[r]; ok {
+		const mx, my = 2, glyphHeight / 2
+		for y := range b {
+			if arms[0] && y <= my || arms[1] && y >= my {
+				b[y] |= 1 << mx
+			}
+		}
+		if arms[2] {
+			b[my] |= 1<<(mx+1) - 1
+		}
+		if arms[3] {
+			b[my] |= full &^ (1<<mx - 1)
+		}
+		return b
+	}
+
+	// An empty box for characters the font does not have
+	w := cells*glyphWidth - 1
+	for y := 1; y < glyphHeight-1; y++ {
+		b[y] = 1 | 1<<(w-1)
+	}
+	b[1] = 1<<w - 1
+	b[glyphHeight-2] = 1<<w - 1
+	return b
+}
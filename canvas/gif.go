@@ -0,0 +1,60 @@
+package canvas
+
+import (
+	"bytes"
+	"image"
+	"image/draw"
+	"image/gif"
+	"time"
+
+	"github.com/vnykmshr/gowsay/cow"
+)
+
+// GIF draws the text of each frame as Image does, as an animated GIF. The
+// animation plays loops times, or forever if loops is 0. Frames of more
+// than MaxPixels pixels, or more than MaxAnimationPixels pixels in all,
+// are not drawn.
+func GIF(frames []cow.Frame, loops int, opts Options) ([]byte, error) {
+	// Every frame is as large as the largest, so check the size before
+	// drawing any
+	var bounds image.Rectangle
+	for _, f := range frames {
+		bounds = bounds.Union(image.Rectangle{Max: Size(f.Text, opts)})
+	}
+	pixels := bounds.Dx() * bounds.Dy()
+	if pixels > MaxPixels {
+		return nil, ErrTooLarge
+	}
+	if pixels*len(frames) > MaxAnimationPixels {
+		return nil, ErrAnimationTooLarge
+	}
+
+	palette := opts.palette()
+	anim := &gif.GIF{LoopCount: loopCount(loops)}
+	for _, f := range frames {
+		img := Image(f.Text, opts)
+		frame := image.NewPaletted(bounds, palette)
+		draw.Draw(frame, img.Bounds(), img, image.Point{}, draw.Src)
+		anim.Image = append(anim.Image, frame)
+		anim.Delay = append(anim.Delay, int(f.Delay/(10*time.Millisecond)))
+	}
+
+	var buf bytes.Buffer
+	if err := gif.EncodeAll(&buf, anim); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// loopCount converts a number of plays to a GIF loop count, which is the
+// number of times the animation restarts, with -1 for none and 0 for
+// forever
+func loopCount(loops int) int {
+	switch {
+	case loops <= 0:
+		return 0
+	case loops == 1:
+		return -1
+	}
+	return loops - 1
+}
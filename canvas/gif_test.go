@@ -0,0 +1,89 @@
+package canvas
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/gif"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vnykmshr/gowsay/cow"
+)
+
+func TestGIF(t *testing.T) {
+	opts := DefaultOptions()
+	opts.Padding = 0
+	frames := []cow.Frame{
+		{Text: "|", Delay: 250 * time.Millisecond},
+		{Text: "\x1b[31m||\x1b[0m\n|", Delay: 2 * time.Second},
+	}
+
+	tests := []struct {
+		loops int
+		want  int
+	}{
+		{0, 0},
+		{1, -1},
+		{3, 2},
+	}
+	for _, tt := range tests {
+		data, err := GIF(frames, tt.loops, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		anim, err := gif.DecodeAll(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("GIF() is not a GIF: %v", err)
+		}
+		if anim.LoopCount != tt.want {
+			t.Errorf("GIF(loops %d) LoopCount = %d, want %d", tt.loops, anim.LoopCount, tt.want)
+		}
+		if len(anim.Image) != 2 || anim.Delay[0] != 25 || anim.Delay[1] != 200 {
+			t.Fatalf("GIF() has %d frames, delays %v, want 2 with [25 200]", len(anim.Image), anim.Delay)
+		}
+		// Frames share the bounds of the largest
+		for i, img := range anim.Image {
+			if got, want := img.Bounds(), image.Rect(0, 0, 2*12, 2*20); got != want {
+				t.Errorf("frame %d bounds = %v, want %v", i, got, want)
+			}
+		}
+	}
+}
+
+func TestGIF_TooLarge(t *testing.T) {
+	opts := DefaultOptions()
+	opts.Scale = MaxScale
+	big := strings.Repeat(strings.Repeat("moo ", 50)+"\n", 20)
+	if _, err := GIF([]cow.Frame{{Text: "|"}, {Text: big}}, 0, opts); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("GIF() with a frame of %v error = %v, want ErrTooLarge", Size(big, opts), err)
+	}
+
+	// Small frames add up
+	opts = DefaultOptions()
+	frame := Size("|", opts)
+	frames := make([]cow.Frame, MaxAnimationPixels/(frame.X*frame.Y)+1)
+	for i := range frames {
+		frames[i].Text = "|"
+	}
+	if _, err := GIF(frames, 0, opts); !errors.Is(err, ErrAnimationTooLarge) {
+		t.Errorf("GIF() of %d frames error = %v, want ErrAnimationTooLarge", len(frames), err)
+	}
+}
+
+func TestGIF_Transparent(t *testing.T) {
+	opts := DefaultOptions()
+	opts.Background, _ = ParseColor("transparent")
+	data, err := GIF([]cow.Frame{{Text: "|"}}, 0, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	anim, err := gif.DecodeAll(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("GIF() is not a GIF: %v", err)
+	}
+	if _, _, _, a := anim.Image[0].At(0, 0).RGBA(); a != 0 {
+		t.Errorf("background alpha = %d, want transparent", a)
+	}
+}
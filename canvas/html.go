@@ -0,0 +1,95 @@
+package canvas
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"image/color"
+	"strings"
+
+	"github.com/vnykmshr/gowsay/ansi"
+)
+
+// HTML writes text, which may contain SGR escape sequences for colors and
+// styles, as an escaped <pre> block with the styles in inline <span>
+// elements, so it keeps its colors in email. The block is styled inline
+// with the font, colors and padding of opts; with zero Options it only has
+// the class "gowsay", for pages that style it themselves.
+func HTML(text string, opts Options) []byte {
+	var buf bytes.Buffer
+	buf.WriteString(`<pre class="gowsay"`)
+	if opts != (Options{}) {
+		fmt.Fprintf(&buf, ` style="margin:0;padding:%dpx;border-radius:6px;font-family:%s;font-size:%dpx;line-height:%s;color:%s;background:%s"`,
+			opts.Padding, html.EscapeString(opts.FontFamily), opts.FontSize, num(lineHeight), css(opts.Foreground), css(opts.Background))
+	}
+	buf.WriteString(">")
+
+	for i, line := range ansi.Parse(strings.TrimSuffix(text, "\n")) {
+		if i > 0 {
+			buf.WriteByte('\n')
+		}
+		for _, run := range line {
+			if decl := declarations(run.Style); decl != "" {
+				fmt.Fprintf(&buf, `<span style="%s">%s</span>`, decl, html.EscapeString(run.Text))
+			} else {
+				buf.WriteString(html.EscapeString(run.Text))
+			}
+		}
+	}
+	buf.WriteString("</pre>\n")
+	return buf.Bytes()
+}
+
+// Page writes text as HTML does, in a standalone HTML page in the colors
+// of opts, with a button that copies the text
+func Page(text string, opts Options) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>gowsay</title>
+<style>
+body { margin: 0; min-height: 100vh; display: flex; flex-direction: column; align-items: center; justify-content: center; gap: 12px; background: %s; }
+button { font: 14px sans-serif; padding: 6px 14px; border: 1px solid %s; border-radius: 6px; background: transparent; color: %[2]s; cursor: pointer; }
+</style>
+</head>
+<body>
+`, css(opts.Background), css(opts.Foreground))
+	buf.Write(HTML(text, opts))
+	buf.WriteString(`<button type="button" onclick="navigator.clipboard.writeText(document.querySelector('pre').innerText).then(() => { this.textContent = 'Copied!'; })">Copy</button>
+</body>
+</html>
+`)
+	return buf.Bytes()
+}
+
+// declarations returns the inline CSS for a style
+func declarations(st ansi.Style) string {
+	var decl []string
+	if c, ok := ansi.Palette[st.Color]; ok {
+		decl = append(decl, "color:"+css(color.NRGBA(c)))
+	}
+	if st.Bold {
+		decl = append(decl, "font-weight:bold")
+	}
+	if st.Italic {
+		decl = append(decl, "font-style:italic")
+	}
+	if st.Strike {
+		decl = append(decl, "text-decoration:line-through")
+	}
+	return strings.Join(decl, ";")
+}
+
+// css returns a color as CSS hex, or "transparent"
+func css(c color.NRGBA) string {
+	switch c.A {
+	case 0:
+		return "transparent"
+	case 0xff:
+		return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+	}
+	return fmt.Sprintf("#%02x%02x%02x%02x", c.R, c.G, c.B, c.A)
+}
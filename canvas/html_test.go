@@ -0,0 +1,70 @@
+package canvas
+
+import (
+	"image/color"
+	"strings"
+	"testing"
+)
+
+func TestHTML(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		opts Options
+		want string
+	}{
+		{"escaped", "<a & \"b\">\n", Options{}, `<pre class="gowsay">&lt;a &amp; &#34;b&#34;&gt;</pre>` + "\n"},
+		{"lines", "a\nb\n", Options{}, `<pre class="gowsay">a` + "\nb</pre>\n"},
+		{"colors", "\x1b[32mok\x1b[0m <\x1b[1;3;9mx\x1b[0m>", Options{},
+			`<pre class="gowsay"><span style="color:#2e9b4f">ok</span> &lt;<span style="font-weight:bold;font-style:italic;text-decoration:line-through">x</span>&gt;</pre>` + "\n"},
+		{"themed", "moo", DefaultOptions(),
+			`<pre class="gowsay" style="margin:0;padding:16px;border-radius:6px;font-family:DejaVu Sans Mono, Menlo, Consolas, monospace;font-size:14px;line-height:1.2;color:#24292f;background:#ffffff">moo</pre>` + "\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(HTML(tt.text, tt.opts)); got != tt.want {
+				t.Errorf("HTML() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPage(t *testing.T) {
+	opts := DefaultOptions()
+	opts.SetTheme("dark")
+	got := string(Page("<moo>", opts))
+	for _, want := range []string{"<!DOCTYPE html>", "background: #0d1117;", "&lt;moo&gt;</pre>", "navigator.clipboard.writeText", "</html>\n"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Page() = %s, want it to contain %q", got, want)
+		}
+	}
+}
+
+func TestSetTheme(t *testing.T) {
+	opts := DefaultOptions()
+	if !opts.SetTheme("Dark") || opts.Background != (color.NRGBA{0x0d, 0x11, 0x17, 0xff}) {
+		t.Errorf("SetTheme(Dark) background = %v", opts.Background)
+	}
+	if opts.SetTheme("sepia") {
+		t.Error("SetTheme(sepia) = true, want false")
+	}
+	if got := strings.Join(Themes(), ","); got != "dark,light" {
+		t.Errorf("Themes() = %s, want dark,light", got)
+	}
+}
+
+func TestCSS(t *testing.T) {
+	tests := []struct {
+		c    color.NRGBA
+		want string
+	}{
+		{color.NRGBA{0x12, 0xab, 0xef, 0xff}, "#12abef"},
+		{color.NRGBA{0x12, 0xab, 0xef, 0x80}, "#12abef80"},
+		{color.NRGBA{}, "transparent"},
+	}
+	for _, tt := range tests {
+		if got := css(tt.c); got != tt.want {
+			t.Errorf("css(%v) = %s, want %s", tt.c, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package canvas
+
+import (
+	"bytes"
+	"image"
+	"image/draw"
+	"image/png"
+
+	"github.com/vnykmshr/gowsay/ansi"
+)
+
+// PNG draws text, which may contain SGR escape sequences for colors and
+// styles, as a PNG image in the embedded bitmap font. Each font pixel is
+// a Scale by Scale square; the font size and family do not apply. Images
+// of more than MaxPixels pixels are not drawn.
+func PNG(text string, opts Options) ([]byte, error) {
+	if s := Size(text, opts); s.X*s.Y > MaxPixels {
+		return nil, ErrTooLarge
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, Image(text, opts)); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Size returns the width and height in pixels of the image PNG draws for
+// text, so callers can check it against MaxPixels before drawing
+func Size(text string, opts Options) image.Point {
+	return size(grid(text), opts)
+}
+
+// size returns the size of the image of rows of cells
+func size(rows [][]cell, opts Options) image.Point {
+	scale := max(opts.Scale, 1)
+	return image.Pt(2*opts.Padding+columns(rows)*glyphWidth*scale, 2*opts.Padding+len(rows)*glyphHeight*scale)
+}
+
+// Image draws text as PNG does, returning the image. It draws any size;
+// PNG limits it to MaxPixels.
+func Image(text string, opts Options) *image.NRGBA {
+	rows := grid(text)
+	scale := max(opts.Scale, 1)
+	cw, ch := glyphWidth*scale, glyphHeight*scale
+	img := image.NewNRGBA(image.Rectangle{Max: size(rows, opts)})
+	draw.Draw(img, img.Bounds(), image.NewUniform(opts.Background), image.Point{}, draw.Src)
+
+	for y, row := range rows {
+		for x, c := range row {
+			if c.text == "" {
+				continue
+			}
+			cells := 1
+			if x+1 < len(row) && row[x+1].text == "" {
+				cells = 2
+			}
+
+			ink := image.Image(image.NewUniform(opts.Foreground))
+			if rgba, ok := ansi.Palette[c.style.Color]; ok {
+				ink = image.NewUniform(rgba)
+			}
+			origin := image.Pt(opts.Padding+x*cw, opts.Padding+y*ch)
+			b := styled(glyph([]rune(c.text)[0], cells), c.style, cells)
+			for gy, bits := range b {
+				for gx := 0; bits != 0; gx, bits = gx+1, bits>>1 {
+					if bits&1 == 0 {
+						continue
+					}
+					r := image.Rect(gx*scale, gy*scale, (gx+1)*scale, (gy+1)*scale).Add(origin)
+					draw.Draw(img, r, ink, image.Point{}, draw.Over)
+				}
+			}
+		}
+	}
+	return img
+}
+
+// styled applies a style to a glyph: bold doubles strokes, italic slants
+// the top rows right and strike draws a line through the middle
+func styled(b bitmap, st ansi.Style, cells int) bitmap {
+	width := uint16(1)<<(cells*glyphWidth) - 1
+	for y := range b {
+		if st.Bold {
+			b[y] |= b[y] << 1
+		}
+		if st.Italic && y < glyphHeight/2 {
+			b[y] <<= 1
+		}
+		if st.Strike && y == glyphHeight/2 {
+			b[y] = width
+		}
+		b[y] &= width
+	}
+	return b
+}
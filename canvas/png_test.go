@@ -0,0 +1,155 @@
+package canvas
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/vnykmshr/gowsay/ansi"
+)
+
+// ascii draws a glyph bitmap as rows of '#' and '.'
+func ascii(b bitmap, width int) string {
+	var rows []string
+	for _, bits := range b {
+		var row strings.Builder
+		for x := range width {
+			if bits&(1<<x) != 0 {
+				row.WriteByte('#')
+			} else {
+				row.WriteByte('.')
+			}
+		}
+		rows = append(rows, row.String())
+	}
+	return strings.Join(rows, "\n")
+}
+
+func TestPNG(t *testing.T) {
+	opts := DefaultOptions()
+	opts.Padding = 3
+	data, err := PNG("|\x1b[32m|\x1b[0m\n牛", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("PNG() is not a PNG: %v", err)
+	}
+
+	// Two rows of two cells, at scale 2, with the padding around them
+	if got, want := img.Bounds(), image.Rect(0, 0, 2*3+2*12, 2*3+2*20); got != want {
+		t.Errorf("PNG() bounds = %v, want %v", got, want)
+	}
+	// A bar runs down the middle of each '|' cell, in its color
+	tests := []struct {
+		x, y int
+		want color.Color
+	}{
+		{3 + 4, 3, opts.Foreground},
+		{3 + 12 + 4, 3 + 19, ansi.Palette[32]},
+		{3, 3, opts.Background},
+		{0, 0, opts.Background},
+	}
+	for _, tt := range tests {
+		r, g, b, a := img.At(tt.x, tt.y).RGBA()
+		wr, wg, wb, wa := tt.want.RGBA()
+		if r != wr || g != wg || b != wb || a != wa {
+			t.Errorf("pixel (%d, %d) = %v, want %v", tt.x, tt.y, img.At(tt.x, tt.y), tt.want)
+		}
+	}
+}
+
+func TestPNG_TooLarge(t *testing.T) {
+	opts := DefaultOptions()
+	opts.Scale = MaxScale
+	line := strings.Repeat("moo ", 50)
+
+	// 200 columns by 48 pixels is 9600 pixels wide at scale 8
+	text := strings.Repeat(line+"\n", 8)
+	if s := Size(text, opts); s.X != 2*opts.Padding+200*glyphWidth*MaxScale {
+		t.Errorf("Size() = %v, want %d pixels wide", s, 2*opts.Padding+200*glyphWidth*MaxScale)
+	}
+	if _, err := PNG(text, opts); err != nil {
+		t.Errorf("PNG() of %v error = %v", Size(text, opts), err)
+	}
+
+	text = strings.Repeat(line+"\n", 20)
+	if _, err := PNG(text, opts); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("PNG() of %v error = %v, want ErrTooLarge", Size(text, opts), err)
+	}
+}
+
+func TestImage_Transparent(t *testing.T) {
+	opts := Options{Scale: 1, Foreground: color.NRGBA{0xff, 0, 0, 0x80}}
+	img := Image("-", opts)
+
+	if got := img.NRGBAAt(0, 0); got != (color.NRGBA{}) {
+		t.Errorf("background = %v, want transparent", got)
+	}
+	if got := img.NRGBAAt(0, glyphHeight/2-1); got != opts.Foreground {
+		t.Errorf("ink = %v, want %v", got, opts.Foreground)
+	}
+}
+
+func TestGlyph(t *testing.T) {
+	tests := []struct {
+		name  string
+		r     rune
+		cells int
+		want  string
+	}{
+		{"font", 'o', 1, "......\n......\n......\n.###..\n#...#.\n#...#.\n#...#.\n.###..\n......\n......"},
+		{"block", '▀', 1, "######\n######\n######\n######\n######\n......\n......\n......\n......\n......"},
+		{"box", '┌', 1, "......\n......\n......\n......\n......\n..####\n..#...\n..#...\n..#...\n..#..."},
+		{"missing", '☃', 1, "......\n#####.\n#...#.\n#...#.\n#...#.\n#...#.\n#...#.\n#...#.\n#####.\n......"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ascii(glyph(tt.r, tt.cells), tt.cells*glyphWidth); got != tt.want {
+				t.Errorf("glyph(%q) =\n%s\nwant\n%s", tt.r, got, tt.want)
+			}
+		})
+	}
+
+	wide := ascii(glyph('牛', 2), 12)
+	if first := strings.Split(wide, "\n")[1]; first != "###########." {
+		t.Errorf("wide missing glyph top = %q, want two cells wide", first)
+	}
+}
+
+func TestStyled(t *testing.T) {
+	b := glyph('l', 1)
+	bold := styled(b, ansi.Style{Bold: true}, 1)
+	if got := ascii(bold, glyphWidth); !strings.Contains(got, "..##..") || !strings.Contains(got, ".####.") {
+		t.Errorf("bold l =\n%s", got)
+	}
+	strike := styled(b, ansi.Style{Strike: true}, 1)
+	if got := strings.Split(ascii(strike, glyphWidth), "\n")[glyphHeight/2]; got != "######" {
+		t.Errorf("strike row = %q", got)
+	}
+}
+
+func TestParseFont(t *testing.T) {
+	font := glyphs()
+	for r := rune(' '); r <= '~'; r++ {
+		if _, ok := font[r]; !ok {
+			t.Errorf("embedded font is missing %q", r)
+		}
+	}
+
+	for _, bad := range []string{
+		"A\n",
+		"U+0041 A\n......\n",
+		"U+0041 A\n" + strings.Repeat("#####\n", glyphHeight),
+	} {
+		if _, err := parseFont(bad); err == nil {
+			t.Errorf("parseFont(%q) should fail", bad)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package canvas
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"image/color"
+	"math"
+	"strconv"
+
+	"github.com/vnykmshr/gowsay/ansi"
+)
+
+// Character cell size relative to the font size, as in most monospace fonts
+const (
+	cellWidth  = 0.6
+	lineHeight = 1.2
+)
+
+// SVG draws text, which may contain SGR escape sequences for colors and
+// styles, as an SVG document. Every run of text is placed at its column,
+// so wide characters line up whatever the font's glyph widths.
+func SVG(text string, opts Options) []byte {
+	rows := grid(text)
+	size := float64(opts.FontSize)
+	cw, lh := size*cellWidth, size*lineHeight
+	pad := float64(opts.Padding)
+	width := 2*pad + float64(columns(rows))*cw
+	height := 2*pad + float64(len(rows))*lh
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%s" height="%s" viewBox="0 0 %[1]s %[2]s">`+"\n", num(width), num(height))
+	if opts.Background.A > 0 {
+		fmt.Fprintf(&buf, `<rect width="100%%" height="100%%"%s/>`+"\n", fill(opts.Background))
+	}
+	fmt.Fprintf(&buf, `<g font-family="%s" font-size="%s"%s xml:space="preserve">`+"\n", escape(opts.FontFamily), num(size), fill(opts.Foreground))
+
+	for i, row := range rows {
+		if len(row) == 0 {
+			continue
+		}
+		fmt.Fprintf(&buf, `<text y="%s">`, num(pad+float64(i)*lh+size))
+		// wide reports whether the cell at col holds a wide character
+		wide := func(col int) bool { return col+1 < len(row) && row[col+1].text == "" }
+		for col := 0; col < len(row); {
+			// Each wide character is a run of its own, placed at its column
+			end := col + 2
+			if !wide(col) {
+				end = col + 1
+				for end < len(row) && row[end].style == row[col].style && !wide(end) {
+					end++
+				}
+			}
+
+			var run []byte
+			for _, c := range row[col:end] {
+				run = append(run, c.text...)
+			}
+			fmt.Fprintf(&buf, `<tspan x="%s"%s>%s</tspan>`, num(pad+float64(col)*cw), attrs(row[col].style), escape(string(run)))
+			col = end
+		}
+		buf.WriteString("</text>\n")
+	}
+	buf.WriteString("</g>\n</svg>\n")
+	return buf.Bytes()
+}
+
+// attrs returns the SVG presentation attributes for a style
+func attrs(st ansi.Style) string {
+	var s string
+	if c, ok := ansi.Palette[st.Color]; ok {
+		s += fill(color.NRGBA(c))
+	}
+	if st.Bold {
+		s += ` font-weight="bold"`
+	}
+	if st.Italic {
+		s += ` font-style="italic"`
+	}
+	if st.Strike {
+		s += ` text-decoration="line-through"`
+	}
+	return s
+}
+
+// fill returns fill attributes for a color
+func fill(c color.NRGBA) string {
+	s := fmt.Sprintf(` fill="#%02x%02x%02x"`, c.R, c.G, c.B)
+	if c.A < 0xff {
+		s += ` fill-opacity="` + num(float64(c.A)/0xff) + `"`
+	}
+	return s
+}
+
+// escape escapes text for XML content and attribute values
+func escape(s string) string {
+	var buf bytes.Buffer
+	xml.EscapeText(&buf, []byte(s))
+	return buf.String()
+}
+
+// num formats a length with at most two decimals
+func num(f float64) string {
+	return strconv.FormatFloat(math.Round(f*100)/100, 'f', -1, 64)
+}
@@ -0,0 +1,128 @@
+package canvas
+
+import (
+	"encoding/xml"
+	"errors"
+	"image/color"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestSVG(t *testing.T) {
+	opts := DefaultOptions()
+	got := string(SVG("< \x1b[35mfunc\x1b[0m & \"q\" >\n牛 moo\n\n", opts))
+
+	for _, want := range []string{
+		`width="149.6" height="82.4" viewBox="0 0 149.6 82.4"`,
+		`<rect width="100%" height="100%" fill="#ffffff"/>`,
+		`font-family="DejaVu Sans Mono, Menlo, Consolas, monospace" font-size="14" fill="#24292f"`,
+		`<text y="30"><tspan x="16">&lt; </tspan><tspan x="32.8" fill="#a84cd1">func</tspan><tspan x="66.4"> &amp; &#34;q&#34; &gt;</tspan></text>`,
+		// The wide character takes two cells
+		`<text y="46.8"><tspan x="16">牛</tspan><tspan x="32.8"> moo</tspan></text>`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("SVG() missing %s:\n%s", want, got)
+		}
+	}
+
+	// The document is well-formed XML
+	d := xml.NewDecoder(strings.NewReader(got))
+	for {
+		_, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("SVG() is not well-formed: %v\n%s", err, got)
+		}
+	}
+}
+
+func TestSVG_Options(t *testing.T) {
+	opts := Options{
+		FontFamily: `"Fira Code" <x>`,
+		FontSize:   10,
+		Foreground: color.NRGBA{0xff, 0, 0, 0x80},
+		Padding:    0,
+	}
+	got := string(SVG("\x1b[1;3;9mab\x1b[0m", opts))
+
+	for _, want := range []string{
+		`width="12" height="12"`,
+		`font-family="&#34;Fira Code&#34; &lt;x&gt;" font-size="10" fill="#ff0000" fill-opacity="0.5"`,
+		`<tspan x="0" font-weight="bold" font-style="italic" text-decoration="line-through">ab</tspan>`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("SVG() missing %s:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "<rect") {
+		t.Errorf("a transparent background should not be drawn:\n%s", got)
+	}
+}
+
+func TestOptions_Validate(t *testing.T) {
+	tests := []struct {
+		name       string
+		set        func(*Options)
+		wantFamily bool
+		wantOption string
+	}{
+		{"defaults", func(*Options) {}, false, ""},
+		{"quoted families", func(o *Options) { o.FontFamily = `"Fira Code", 'Noto Sans JP', monospace` }, false, ""},
+		{"non-latin family", func(o *Options) { o.FontFamily = "ヒラギノ角ゴ" }, false, ""},
+		{"limits", func(o *Options) { o.FontSize, o.Scale, o.Padding = MaxFontSize, MaxScale, 0 }, false, ""},
+		{"css injection", func(o *Options) { o.FontFamily = "x;background:url(//evil)" }, true, ""},
+		{"closing tag", func(o *Options) { o.FontFamily = "mono</pre>" }, true, ""},
+		{"newline", func(o *Options) { o.FontFamily = "a\nb" }, true, ""},
+		{"font too small", func(o *Options) { o.FontSize = MinFontSize - 1 }, false, OptionFontSize},
+		{"no scale", func(o *Options) { o.Scale = 0 }, false, OptionScale},
+		{"padding too wide", func(o *Options) { o.Padding = MaxPadding + 1 }, false, OptionPadding},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := DefaultOptions()
+			tt.set(&opts)
+			err := opts.Validate()
+			var family *FontFamilyError
+			var rangeErr *RangeError
+			switch {
+			case tt.wantFamily:
+				if !errors.As(err, &family) || family.Family != opts.FontFamily {
+					t.Errorf("Validate() = %v, want a font family error", err)
+				}
+			case tt.wantOption != "":
+				if !errors.As(err, &rangeErr) || rangeErr.Option != tt.wantOption {
+					t.Errorf("Validate() = %v, want a %s range error", err, tt.wantOption)
+				}
+			case err != nil:
+				t.Errorf("Validate() = %v", err)
+			}
+		})
+	}
+}
+
+func TestParseColor(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    color.NRGBA
+		wantErr bool
+	}{
+		{"#fff", color.NRGBA{0xff, 0xff, 0xff, 0xff}, false},
+		{"#1e2F3a", color.NRGBA{0x1e, 0x2f, 0x3a, 0xff}, false},
+		{"#00000080", color.NRGBA{0, 0, 0, 0x80}, false},
+		{"transparent", color.NRGBA{}, false},
+		{"fff", color.NRGBA{}, true},
+		{"#ggg", color.NRGBA{}, true},
+		{"#12345", color.NRGBA{}, true},
+		{"red", color.NRGBA{}, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseColor(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseColor(%q) = %v, %v, want %v (error %v)", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package canvas
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/draw"
+	"strings"
+)
+
+// Terminal graphics protocols
+const (
+	GraphicsSixel = "sixel"
+	GraphicsKitty = "kitty"
+)
+
+// kittyChunk is the most base64 data the kitty protocol takes in one
+// escape sequence
+const kittyChunk = 4096
+
+// DetectGraphics returns the graphics protocol of the terminal described
+// by the TERM and TERM_PROGRAM environment variables, read with getenv, or
+// "" if it is not known to support one. Terminals are only matched by
+// names that are set by the terminal itself, not by the user's shell.
+func DetectGraphics(getenv func(string) string) string {
+	term, program := getenv("TERM"), getenv("TERM_PROGRAM")
+	switch {
+	case term == "xterm-kitty", term == "xterm-ghostty", getenv("KITTY_WINDOW_ID") != "":
+		return GraphicsKitty
+	case program == "ghostty", program == "WezTerm":
+		return GraphicsKitty
+	case term == "foot", strings.HasPrefix(term, "foot-"), term == "mlterm":
+		return GraphicsSixel
+	case program == "iTerm.app", program == "mintty":
+		return GraphicsSixel
+	}
+	return ""
+}
+
+// Sixel draws text as Image does, as a DEC sixel escape sequence for
+// terminals that show inline images. A transparent background leaves the
+// terminal's own background showing. Like PNG, it does not draw images of
+// more than MaxPixels pixels.
+func Sixel(text string, opts Options) ([]byte, error) {
+	if s := Size(text, opts); s.X*s.Y > MaxPixels {
+		return nil, ErrTooLarge
+	}
+	img := Image(text, opts)
+	bounds := img.Bounds()
+	palette := opts.palette()
+	pix := image.NewPaletted(bounds, palette)
+	draw.Draw(pix, bounds, img, bounds.Min, draw.Src)
+
+	var buf bytes.Buffer
+	// Pixels left at 0 keep the background; the raster is 1:1 pixels
+	fmt.Fprintf(&buf, "\x1bP0;1;0q\"1;1;%d;%d", bounds.Dx(), bounds.Dy())
+
+	// Color registers, in percent, for the colors the image uses
+	used := make([]bool, len(palette))
+	for _, i := range pix.Pix {
+		used[i] = true
+	}
+	for i, c := range palette {
+		r, g, b, a := c.RGBA()
+		if !used[i] || a == 0 {
+			used[i] = false
+			continue
+		}
+		fmt.Fprintf(&buf, "#%d;2;%d;%d;%d", i, percent(r), percent(g), percent(b))
+	}
+
+	// Bands of six rows, with a pass over the band for each color
+	for y := 0; y < bounds.Dy(); y += 6 {
+		if y > 0 {
+			buf.WriteByte('-')
+		}
+		first := true
+		for i := range palette {
+			if !used[i] {
+				continue
+			}
+			row := make([]byte, bounds.Dx())
+			ink := false
+			for x := range row {
+				bits := byte(0)
+				for dy := 0; dy < 6 && y+dy < bounds.Dy(); dy++ {
+					if pix.ColorIndexAt(x, y+dy) == uint8(i) {
+						bits |= 1 << dy
+					}
+				}
+				row[x] = '?' + bits
+				ink = ink || bits != 0
+			}
+			if !ink {
+				continue
+			}
+			if !first {
+				buf.WriteByte('$')
+			}
+			first = false
+			fmt.Fprintf(&buf, "#%d", i)
+			writeSixels(&buf, bytes.TrimRight(row, "?"))
+		}
+	}
+	buf.WriteString("\x1b\\\n")
+	return buf.Bytes(), nil
+}
+
+// writeSixels writes a row of sixels, run-length encoding repeats
+func writeSixels(buf *bytes.Buffer, row []byte) {
+	for len(row) > 0 {
+		n := 1
+		for n < len(row) && row[n] == row[0] {
+			n++
+		}
+		if n > 3 {
+			fmt.Fprintf(buf, "!%d%c", n, row[0])
+		} else {
+			buf.Write(row[:n])
+		}
+		row = row[n:]
+	}
+}
+
+// percent scales a 16-bit color channel to 0-100
+func percent(v uint32) uint32 {
+	return (v*100 + 0xffff/2) / 0xffff
+}
+
+// Kitty draws text as PNG does, as a kitty graphics protocol escape
+// sequence for terminals that show inline images
+func Kitty(text string, opts Options) ([]byte, error) {
+	data, err := PNG(text, opts)
+	if err != nil {
+		return nil, err
+	}
+	return kitty(data), nil
+}
+
+// kitty wraps PNG data in kitty graphics escape sequences, splitting the
+// base64 payload into chunks
+func kitty(data []byte) []byte {
+	payload := base64.StdEncoding.EncodeToString(data)
+	var buf bytes.Buffer
+	for first := true; first || payload != ""; first = false {
+		chunk := payload[:min(kittyChunk, len(payload))]
+		payload = payload[len(chunk):]
+		more := 0
+		if payload != "" {
+			more = 1
+		}
+		if first {
+			fmt.Fprintf(&buf, "\x1b_Ga=T,f=100,m=%d;%s\x1b\\", more, chunk)
+		} else {
+			fmt.Fprintf(&buf, "\x1b_Gm=%d;%s\x1b\\", more, chunk)
+		}
+	}
+	buf.WriteByte('\n')
+	return buf.Bytes()
+}
@@ -0,0 +1,126 @@
+package canvas
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// golden compares got with the contents of testdata/name, or rewrites the
+// file with -update
+func golden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("output differs from %s (go test -update to rewrite it):\ngot  %q\nwant %q", path, got, want)
+	}
+}
+
+func TestSixel(t *testing.T) {
+	sixel := func(text string, opts Options) []byte {
+		t.Helper()
+		data, err := Sixel(text, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return data
+	}
+
+	opts := DefaultOptions()
+	opts.Padding = 2
+	golden(t, "moo.sixel", sixel("<\x1b[31mmoo\x1b[0m>", opts))
+
+	opts.Background, _ = ParseColor("transparent")
+	opts.Scale = 1
+	golden(t, "moo-transparent.sixel", sixel("(oo)\n \x1b[1m||\x1b[0m", opts))
+}
+
+func TestSixel_TooLarge(t *testing.T) {
+	opts := DefaultOptions()
+	opts.Scale = MaxScale
+	text := strings.Repeat(strings.Repeat("moo ", 50)+"\n", 20)
+	if _, err := Sixel(text, opts); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("Sixel() of %v error = %v, want ErrTooLarge", Size(text, opts), err)
+	}
+}
+
+func TestKitty(t *testing.T) {
+	golden(t, "small.kitty", kitty([]byte("moo")))
+
+	// A payload over one chunk is split, with m=1 on all but the last
+	data := bytes.Repeat([]byte{0x00, 0x10, 0x83}, kittyChunk)
+	golden(t, "chunked.kitty", kitty(data))
+}
+
+func TestKitty_PNG(t *testing.T) {
+	data, err := PNG("moo", DefaultOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := Kitty("moo", DefaultOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := kitty(data); !bytes.Equal(got, want) {
+		t.Errorf("Kitty() does not wrap the PNG rendering")
+	}
+}
+
+func TestWriteSixels(t *testing.T) {
+	tests := []struct {
+		row  string
+		want string
+	}{
+		{"", ""},
+		{"~~~", "~~~"},
+		{"~~~~", "!4~"},
+		{"@AAAAAB", "@!5AB"},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		writeSixels(&buf, []byte(tt.row))
+		if got := buf.String(); got != tt.want {
+			t.Errorf("writeSixels(%q) = %q, want %q", tt.row, got, tt.want)
+		}
+	}
+}
+
+func TestDetectGraphics(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want string
+	}{
+		{"kitty", map[string]string{"TERM": "xterm-kitty"}, GraphicsKitty},
+		{"kitty over ssh", map[string]string{"TERM": "xterm-256color", "KITTY_WINDOW_ID": "1"}, GraphicsKitty},
+		{"ghostty", map[string]string{"TERM": "xterm-ghostty", "TERM_PROGRAM": "ghostty"}, GraphicsKitty},
+		{"wezterm", map[string]string{"TERM": "xterm-256color", "TERM_PROGRAM": "WezTerm"}, GraphicsKitty},
+		{"foot", map[string]string{"TERM": "foot-extra"}, GraphicsSixel},
+		{"iterm", map[string]string{"TERM": "xterm-256color", "TERM_PROGRAM": "iTerm.app"}, GraphicsSixel},
+		{"xterm", map[string]string{"TERM": "xterm-256color"}, ""},
+		{"apple terminal", map[string]string{"TERM": "xterm-256color", "TERM_PROGRAM": "Apple_Terminal"}, ""},
+		{"none", nil, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DetectGraphics(func(k string) string { return tt.env[k] }); got != tt.want {
+				t.Errorf("DetectGraphics() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,225 @@
+package cow
+
+import (
+	"fmt"
+	"image"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// Anchors are the points on a cow that accessories are drawn at
+const (
+	AnchorHead = "head" // the middle of the top of the head
+	AnchorEyes = "eyes" // the left eye
+	AnchorHoof = "hoof" // the front hoof
+)
+
+// accessory is a small sprite drawn over a cow, with the cell of the
+// sprite at the given position placed on the anchor. Spaces in the sprite
+// are transparent.
+type accessory struct {
+	anchor string
+	at     image.Point
+	sprite []string
+}
+
+var accessories = map[string]accessory{
+	"santa-hat": {AnchorHead, image.Pt(4, 2), []string{
+		"  ,--.",
+		" /    `o",
+		"(______)",
+	}},
+	"party-hat": {AnchorHead, image.Pt(2, 2), []string{
+		"  o",
+		" / \\",
+		"/___\\",
+	}},
+	"sunglasses": {AnchorEyes, image.Pt(0, 0), []string{"##"}},
+	"mug":        {AnchorHoof, image.Pt(4, 0), []string{"c[_]"}},
+}
+
+var accessoryNames = []string{"mug", "party-hat", "santa-hat", "sunglasses"}
+
+// anchors are the head and hoof positions of cows that declare them, as
+// column and row in the drawn cow. The eyes anchor is where the {{.Eyes}}
+// placeholder is, unless a cow declares one.
+var anchors = map[string]map[string]image.Point{
+	"default":    {AnchorHead: {14, 0}, AnchorHoof: {16, 4}},
+	"koala":      {AnchorHead: {8, 2}, AnchorHoof: {5, 6}},
+	"moose":      {AnchorHoof: {15, 6}},
+	"sheep":      {AnchorHead: {8, 2}, AnchorHoof: {12, 7}},
+	"three-eyes": {AnchorHead: {13, 0}, AnchorHoof: {16, 4}},
+	"tux":        {AnchorHead: {10, 2}, AnchorHoof: {4, 8}},
+	"www":        {AnchorHead: {14, 0}, AnchorHoof: {16, 4}},
+}
+
+// Accessories returns the names of the accessories
+func Accessories() []string {
+	return slices.Clone(accessoryNames)
+}
+
+// UnknownAccessoryError is returned by ParseAccessories for an accessory
+// name that does not exist
+type UnknownAccessoryError struct {
+	Name string
+}
+
+func (e *UnknownAccessoryError) Error() string {
+	return fmt.Sprintf("accessory '%s' not found", e.Name)
+}
+
+// AnchorConflictError is returned by ParseAccessories for two accessories
+// drawn at the same anchor, which would be drawn over each other
+type AnchorConflictError struct {
+	First  string
+	Second string
+	Anchor string
+}
+
+func (e *AnchorConflictError) Error() string {
+	return fmt.Sprintf("accessories '%s' and '%s' both go on the %s anchor", e.First, e.Second, e.Anchor)
+}
+
+// ParseAccessories looks up a comma-separated list of accessories, such as
+// "santa-hat,sunglasses". Only one accessory may go on each anchor; a
+// repeated accessory is worn once.
+func ParseAccessories(s string) ([]string, error) {
+	var names []string
+	worn := make(map[string]string)
+	for _, name := range ParseList(s) {
+		a, ok := accessories[name]
+		if !ok {
+			return nil, &UnknownAccessoryError{Name: name}
+		}
+		if first, ok := worn[a.anchor]; ok {
+			if first != name {
+				return nil, &AnchorConflictError{First: first, Second: name, Anchor: a.anchor}
+			}
+			continue
+		}
+		worn[a.anchor] = name
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+// MissingAnchorError is returned by CheckAccessories for an accessory the
+// cow has no anchor for
+type MissingAnchorError struct {
+	Cow       string
+	Accessory string
+	Anchor    string
+}
+
+func (e *MissingAnchorError) Error() string {
+	return fmt.Sprintf("cow '%s' has no %s anchor for '%s'", e.Cow, e.Anchor, e.Accessory)
+}
+
+// CheckAccessories reports whether the named cow has the anchors for the
+// accessories. Renderers skip accessories a cow cannot wear, so callers
+// check first to tell the user why.
+func CheckAccessories(cowName string, names []string) error {
+	points, _ := findAnchors(newFace(cowName, ""))
+	for _, name := range names {
+		a, ok := accessories[name]
+		if !ok {
+			return &UnknownAccessoryError{Name: name}
+		}
+		if _, ok := points[a.anchor]; !ok {
+			return &MissingAnchorError{Cow: cowName, Accessory: name, Anchor: a.anchor}
+		}
+	}
+	return nil
+}
+
+// findAnchors returns the anchors of the cow drawn with the face, and the
+// column of the thoughts connector on its first line, or -1 if there is
+// none there
+func findAnchors(f *Face) (map[string]image.Point, int) {
+	points := maps.Clone(anchors[f.cowfile])
+	if points == nil {
+		points = make(map[string]image.Point)
+	}
+	marked := *f
+	marked.Thoughts, marked.Eyes = "\x00", "\x01\x01"
+	art, err := execute(&marked)
+	if err != nil {
+		return points, -1
+	}
+
+	connector := -1
+	for y, line := range strings.Split(art, "\n") {
+		for x, c := range []rune(line) {
+			if _, ok := points[AnchorEyes]; c == '\x01' && !ok {
+				points[AnchorEyes] = image.Pt(x, y)
+			}
+			if c == '\x00' && y == 0 && connector < 0 {
+				connector = x
+			}
+		}
+	}
+	return points, connector
+}
+
+// wear draws the face's accessories over the cow's art. Sprites that
+// reach above the art add lines to the top of it, continuing the thoughts
+// connector up to the balloon.
+func wear(art string, f *Face) string {
+	points, connector := findAnchors(f)
+	var grid [][]rune
+	for _, line := range strings.Split(art, "\n") {
+		grid = append(grid, []rune(line))
+	}
+
+	for _, name := range f.accessories {
+		a := accessories[name]
+		p, ok := points[a.anchor]
+		if !ok {
+			continue
+		}
+		top := p.Y - a.at.Y
+		if top < 0 {
+			added := make([][]rune, -top)
+			for i := range added {
+				if connector >= 0 {
+					col := max(connector+top+i, 0)
+					added[i] = []rune(strings.Repeat(" ", col) + f.Thoughts)
+				}
+			}
+			grid = append(added, grid...)
+			if connector >= 0 {
+				connector = max(connector+top, 0)
+			}
+			for k, pt := range points {
+				points[k] = pt.Add(image.Pt(0, -top))
+			}
+			top = 0
+		}
+
+		for len(grid) < top+len(a.sprite) {
+			grid = append(grid, nil)
+		}
+		left := p.X - a.at.X
+		for y, row := range a.sprite {
+			line := grid[top+y]
+			for x, c := range []rune(row) {
+				col := left + x
+				if c == ' ' || col < 0 {
+					continue
+				}
+				for len(line) <= col {
+					line = append(line, ' ')
+				}
+				line[col] = c
+			}
+			grid[top+y] = line
+		}
+	}
+
+	lines := make([]string, len(grid))
+	for i, line := range grid {
+		lines[i] = string(line)
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,140 @@
+package cow
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParseAccessories(t *testing.T) {
+	got, err := ParseAccessories("santa-hat, sunglasses")
+	if err != nil || !slices.Equal(got, []string{"santa-hat", "sunglasses"}) {
+		t.Errorf("ParseAccessories() = %v, %v", got, err)
+	}
+	if got, err := ParseAccessories(""); err != nil || got != nil {
+		t.Errorf("ParseAccessories(\"\") = %v, %v, want none", got, err)
+	}
+	var unknown *UnknownAccessoryError
+	if _, err := ParseAccessories("mug,cape"); !errors.As(err, &unknown) || unknown.Name != "cape" {
+		t.Errorf("ParseAccessories(cape) error = %v, want UnknownAccessoryError", err)
+	}
+
+	var conflict *AnchorConflictError
+	_, err = ParseAccessories("santa-hat,mug,party-hat")
+	if !errors.As(err, &conflict) || conflict.First != "santa-hat" || conflict.Second != "party-hat" || conflict.Anchor != AnchorHead {
+		t.Errorf("ParseAccessories(santa-hat,party-hat) error = %v, want AnchorConflictError", err)
+	}
+	if got, err := ParseAccessories("mug,mug"); err != nil || !slices.Equal(got, []string{"mug"}) {
+		t.Errorf("ParseAccessories(mug,mug) = %v, %v, want [mug]", got, err)
+	}
+}
+
+func TestSelector_Accessories(t *testing.T) {
+	sel := Selector{Rand: NewRand(1), Accessories: []string{"sunglasses", "santa-hat"}}
+	for i := 0; i < 50; i++ {
+		name := sel.RandomCow()
+		if err := CheckAccessories(name, sel.Accessories); err != nil {
+			t.Fatalf("RandomCow() = %s, which cannot wear %v: %v", name, sel.Accessories, err)
+		}
+	}
+
+	// Only a few cows have a hoof anchor
+	sel.Accessories = []string{"mug"}
+	sel.Exclude = []string{"default", "koala", "moose", "sheep", "three-eyes", "tux", "www"}
+	if name, ok := sel.RandomTagged(""); ok {
+		t.Errorf("RandomTagged() = %s, want no cow with a hoof anchor", name)
+	}
+}
+
+func TestCheckAccessories(t *testing.T) {
+	tests := []struct {
+		cow         string
+		accessories []string
+		wantAnchor  string
+	}{
+		{"default", Accessories(), ""},
+		{"sheep", []string{"sunglasses", "party-hat", "mug"}, ""},
+		{"tux", []string{"santa-hat", "mug"}, ""},
+		{"tux", []string{"sunglasses"}, AnchorEyes},
+		{"moose", []string{"sunglasses", "party-hat"}, AnchorHead},
+		{"mech-and-cow", []string{"mug"}, AnchorHoof},
+	}
+	for _, tt := range tests {
+		t.Run(tt.cow, func(t *testing.T) {
+			err := CheckAccessories(tt.cow, tt.accessories)
+			var missing *MissingAnchorError
+			switch {
+			case tt.wantAnchor == "" && err != nil:
+				t.Errorf("CheckAccessories(%s, %v) error = %v", tt.cow, tt.accessories, err)
+			case tt.wantAnchor != "" && (!errors.As(err, &missing) || missing.Anchor != tt.wantAnchor || missing.Cow != tt.cow):
+				t.Errorf("CheckAccessories(%s, %v) error = %v, want no %s anchor", tt.cow, tt.accessories, err, tt.wantAnchor)
+			}
+		})
+	}
+}
+
+func TestRender_Accessories(t *testing.T) {
+	tests := []struct {
+		name        string
+		cow         string
+		action      string
+		accessories []string
+		want        string
+	}{
+		{"hat and sunglasses", "default", ActionSay, []string{"santa-hat", "sunglasses"}, `      \     ,--.
+       \   /    ` + "`" + `o
+        \ (______)
+         \  (##)\_______
+            (__)\       )\/\
+                ||----w |
+                ||     ||
+`},
+		{"think", "default", ActionThink, []string{"party-hat"}, `      o       o
+       o     / \
+        o   /___\
+         o  (oo)\_______
+            (__)\       )\/\
+                ||----w |
+                ||     ||
+`},
+		{"below the connector", "sheep", ActionSay, []string{"party-hat", "mug"}, `  \     o
+   \   / \
+      /___\
+      UooU\.'@@@@@@'.
+      \__/(@@@@@@@@@@)
+           (@@@@@@@@)
+           'YY~~~~YY'
+        c[_]||    ||
+`},
+		{"missing anchor", "tux", ActionSay, []string{"sunglasses"}, `   \
+    \
+        .--.
+       |o_o |
+       |:_/ |
+      //   \ \
+     (|     | )
+    /'\_   _/'\
+    \___)=(___/
+`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := (&Renderer{Accessories: tt.accessories}).Render([]string{"moo"}, tt.cow, "", tt.action, 40)
+			_, cow, _ := strings.Cut(got, " -----\n")
+			if cow != tt.want {
+				t.Errorf("Render() cow =\n%s\nwant\n%s", cow, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnimate_Accessories(t *testing.T) {
+	// Sunglasses stay on while the cow blinks
+	frames := (&Renderer{Accessories: []string{"sunglasses"}}).Animate([]string{EffectBlink}, []string{"moo"}, "default", "", ActionSay, 40)
+	for i, f := range frames {
+		if !strings.Contains(f.Text, "(##)") {
+			t.Errorf("frame %d has no sunglasses:\n%s", i, f.Text)
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package cow
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Frame is one frame of an animation: the rendered cow and how long it is
+// shown
+type Frame struct {
+	Text  string
+	Delay time.Duration
+}
+
+// Animation effects
+const (
+	EffectBlink = "blink"
+	EffectType  = "type"
+	EffectWag   = "wag"
+)
+
+var effectNames = []string{EffectBlink, EffectType, EffectWag}
+
+// Animation timing: typing runs at most maxTypeFrames frames, then the cow
+// idles for idleTicks frames, blinking on blinkTick
+const (
+	typeDelay     = 50 * time.Millisecond
+	maxTypeFrames = 60
+	idleDelay     = 250 * time.Millisecond
+	idleTicks     = 8
+	blinkTick     = 5
+	holdDelay     = 2 * time.Second
+)
+
+// tails are the tail positions of cows with a {{.Tail}} placeholder, at
+// rest first, then wagged
+var tails = map[string][2]string{
+	"default": {`\/\`, `/\/`},
+}
+
+// Tail returns the named cow's tail at rest, or "" for cows without one
+func Tail(name string) string {
+	return tails[name][0]
+}
+
+// Effects returns the names of the animation effects
+func Effects() []string {
+	return slices.Clone(effectNames)
+}
+
+// UnknownEffectError is returned by ParseEffects for an effect name that
+// does not exist
+type UnknownEffectError struct {
+	Name string
+}
+
+func (e *UnknownEffectError) Error() string {
+	return fmt.Sprintf("animation '%s' not found", e.Name)
+}
+
+// ParseEffects looks up a comma-separated list of animation effects, such
+// as "type,blink"
+func ParseEffects(s string) ([]string, error) {
+	var effects []string
+	for _, name := range ParseList(s) {
+		if !slices.Contains(effectNames, name) {
+			return nil, &UnknownEffectError{Name: name}
+		}
+		effects = append(effects, name)
+	}
+	return effects, nil
+}
+
+// Animate renders the frames of an animation. With EffectType the message
+// is typed out a letter at a time in a balloon of its final size; then,
+// with EffectBlink or EffectWag, the cow idles, blinking its eyes (the
+// mood's closed eyes) and wagging its tail if it has one. The last frame
+// is held for a while before the animation loops.
+func (r *Renderer) Animate(effects []string, text []string, cowName, mood, action string, columns int) []Frame {
+	lines := r.layout(text, columns)
+	width := linesWidth(lines)
+	rest := newFace(cowName, mood)
+
+	var frames []Frame
+	if slices.Contains(effects, EffectType) {
+		total := 0
+		for _, line := range lines {
+			total += utf8.RuneCountInString(spanText(line))
+		}
+		step := max(1, (total+maxTypeFrames-1)/maxTypeFrames)
+		for n := 0; n < total; n += step {
+			face := *rest
+			frames = append(frames, Frame{Text: r.draw(typed(lines, n), width, &face, action), Delay: typeDelay})
+		}
+	}
+
+	blink, wag := slices.Contains(effects, EffectBlink), slices.Contains(effects, EffectWag)
+	if blink || wag {
+		for tick := range idleTicks {
+			face := *rest
+			if wag && tick%2 == 1 {
+				face.Tail = tails[cowName][1]
+			}
+			if blink && tick == blinkTick {
+				face.Eyes = face.blink
+			}
+			frames = append(frames, Frame{Text: r.draw(lines, width, &face, action), Delay: idleDelay})
+		}
+	}
+
+	face := *rest
+	return append(frames, Frame{Text: r.draw(lines, width, &face, action), Delay: holdDelay})
+}
+
+// typed returns the lines with only their first n characters, keeping the
+// number of lines
+func typed(lines [][]span, n int) [][]span {
+	out := make([][]span, len(lines))
+	for i, line := range lines {
+		for _, s := range line {
+			if n <= 0 {
+				break
+			}
+			count := utf8.RuneCountInString(s.text)
+			if count > n {
+				s.text = string([]rune(s.text)[:n])
+			}
+			out[i] = append(out[i], s)
+			n -= count
+		}
+	}
+	return out
+}
+
+// Play shows the frames in a terminal, drawing each over the last, and
+// repeats them loops times, or until ctx is done if loops is 0. The
+// cursor is hidden while playing.
+func Play(ctx context.Context, w io.Writer, frames []Frame, loops int) error {
+	if _, err := io.WriteString(w, "\x1b[?25l"); err != nil {
+		return err
+	}
+	defer io.WriteString(w, "\x1b[?25h")
+
+	height := 0
+	for loop := 0; loops == 0 || loop < loops; loop++ {
+		for _, f := range frames {
+			var sb strings.Builder
+			if height > 0 {
+				// Back to the first line of the last frame, clearing it
+				fmt.Fprintf(&sb, "\x1b[%dF\x1b[J", height)
+			}
+			sb.WriteString(f.Text)
+			if _, err := io.WriteString(w, sb.String()); err != nil {
+				return err
+			}
+			height = strings.Count(f.Text, "\n")
+
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(f.Delay):
+			}
+		}
+	}
+	return nil
+}
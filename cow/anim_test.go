@@ -0,0 +1,93 @@
+package cow
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseEffects(t *testing.T) {
+	effects, err := ParseEffects("type, blink")
+	if err != nil {
+		t.Fatalf("ParseEffects() error = %v", err)
+	}
+	if strings.Join(effects, ",") != "type,blink" {
+		t.Errorf("ParseEffects() = %v, want [type blink]", effects)
+	}
+
+	var unknown *UnknownEffectError
+	if _, err := ParseEffects("wag,spin"); !errors.As(err, &unknown) || unknown.Name != "spin" {
+		t.Errorf("ParseEffects(wag,spin) error = %v, want unknown animation spin", err)
+	}
+}
+
+func TestRenderer_Animate(t *testing.T) {
+	r := &Renderer{}
+	still := r.Render([]string{"moo"}, "default", "tired", ActionSay, 40)
+
+	tests := []struct {
+		name    string
+		effects []string
+		want    []string // substrings, one per frame that has them, in order
+	}{
+		{"none", nil, nil},
+		{"type", []string{EffectType}, []string{"< m   >", "< mo  >", "< moo >"}},
+		{"blink", []string{EffectBlink}, []string{"(__)\\_______"}},
+		{"wag", []string{EffectWag}, []string{")\\/\\", ")/\\/", ")\\/\\"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			frames := r.Animate(tt.effects, []string{"moo"}, "default", "tired", ActionSay, 40)
+			last := frames[len(frames)-1]
+			if last.Text != still || last.Delay != holdDelay {
+				t.Errorf("last frame = %q after %v, want the still cow held %v", last.Text, last.Delay, holdDelay)
+			}
+
+			next := 0
+			for _, f := range frames {
+				if next < len(tt.want) && strings.Contains(f.Text, tt.want[next]) {
+					next++
+				}
+				if lines, want := strings.Count(f.Text, "\n"), strings.Count(still, "\n"); lines != want {
+					t.Errorf("frame has %d lines, want %d:\n%s", lines, want, f.Text)
+				}
+			}
+			if next != len(tt.want) {
+				t.Errorf("frames missing %q", tt.want[next])
+			}
+		})
+	}
+}
+
+func TestRenderer_AnimateLongText(t *testing.T) {
+	r := &Renderer{}
+	frames := r.Animate([]string{EffectType}, []string{strings.Repeat("moo ", 100)}, "default", "", ActionSay, 40)
+	if len(frames) > maxTypeFrames+1 {
+		t.Errorf("Animate() typed in %d frames, want at most %d", len(frames)-1, maxTypeFrames)
+	}
+}
+
+func TestPlay(t *testing.T) {
+	frames := []Frame{{Text: "a\nb\n"}, {Text: "c\nd\n"}}
+	var buf bytes.Buffer
+	if err := Play(context.Background(), &buf, frames, 2); err != nil {
+		t.Fatalf("Play() error = %v", err)
+	}
+	want := "\x1b[?25l" + "a\nb\n" + "\x1b[2F\x1b[J" + "c\nd\n" + "\x1b[2F\x1b[J" + "a\nb\n" + "\x1b[2F\x1b[J" + "c\nd\n" + "\x1b[?25h"
+	if got := buf.String(); got != want {
+		t.Errorf("Play() wrote %q, want %q", got, want)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	buf.Reset()
+	if err := Play(ctx, &buf, []Frame{{Text: "a\n", Delay: time.Hour}}, 0); !errors.Is(err, context.Canceled) {
+		t.Errorf("Play() with a cancelled context error = %v, want context.Canceled", err)
+	}
+	if !strings.HasSuffix(buf.String(), "\x1b[?25h") {
+		t.Errorf("Play() did not show the cursor again: %q", buf.String())
+	}
+}
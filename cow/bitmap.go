@@ -0,0 +1,122 @@
+package cow
+
+import (
+	"image"
+	"strings"
+)
+
+// Art styles for bitmap cows: Unicode half blocks, two pixels to a
+// character cell, or braille patterns, eight
+const (
+	StyleHalfBlock = "half"
+	StyleBraille   = "braille"
+)
+
+// halfBlocks are the characters for the top and bottom pixels of a cell,
+// indexed by top | bottom<<1
+var halfBlocks = [4]string{" ", "▀", "▄", "█"}
+
+// brailleDots are the bits of the braille pattern for each pixel of a
+// two by four cell, by row and column
+var brailleDots = [4][2]rune{{0x01, 0x08}, {0x02, 0x10}, {0x04, 0x20}, {0x40, 0x80}}
+
+// bitmapCow is a cow drawn from a bitmap, '#' for ink. Eyes is the pixel
+// at the top left of where the eyes go, in a gap two character cells wide;
+// the thoughts connector leads down to the art at column Indent.
+type bitmapCow struct {
+	Pixels []string
+	Eyes   image.Point
+	Indent int
+}
+
+// bitmapCows are the cows drawn from bitmaps, by name
+var bitmapCows = map[string]bitmapCow{
+	"cow": {
+		Pixels: []string{
+			`.#...............#..............................`,
+			`..#.............#...............................`,
+			`...#..######...#................................`,
+			`....##......##..................................`,
+			`...##........##.........##########..............`,
+			`...#..........#......###..........###.....#.....`,
+			`..#............#...##................##...#.....`,
+			`..#............#..#...######...........#..#.....`,
+			`..#............#.#....######............#..#....`,
+			`..#............##.....######.............#.#....`,
+			`..#............##.....######.............#.#....`,
+			`..#...######...##...............####.....#..#...`,
+			`...#.########.#.#..............######....#..#...`,
+			`...####.###.###..#.............######...#...#...`,
+			`....##########....#.............####...#....##..`,
+			`......######.......##................##.....##..`,
+			`.....................###....#.....###...........`,
+			`...................##...##########...##.........`,
+			`...................##...#######.##...##.........`,
+			`...................##...##..#...##...##.........`,
+			`...................##...##......##...##.........`,
+			`...................##...##......##...##.........`,
+			`...................##...##......##...##.........`,
+			`...................##...##......##...##.........`,
+			`..................###..###.....###..###.........`,
+		},
+		Eyes:   image.Pt(8, 6),
+		Indent: 4,
+	},
+}
+
+// template draws a bitmap cow in a style, as a cow template with the
+// thoughts connector and eyes placeholders
+func (b bitmapCow) template(style string) string {
+	cw, ch := 1, 2
+	if style == StyleBraille {
+		cw, ch = 2, 4
+	}
+	ink := func(x, y int) bool {
+		return y < len(b.Pixels) && x < len(b.Pixels[y]) && b.Pixels[y][x] == '#'
+	}
+
+	var sb strings.Builder
+	sb.WriteString(strings.Repeat(" ", b.Indent) + "{{.Thoughts}}\n")
+	sb.WriteString(strings.Repeat(" ", b.Indent+1) + "{{.Thoughts}}\n")
+	eyes := image.Pt(b.Eyes.X/cw, b.Eyes.Y/ch)
+	for row := 0; row*ch < len(b.Pixels); row++ {
+		var line strings.Builder
+		for col := 0; col*cw < len(b.Pixels[0]); col++ {
+			switch {
+			case row == eyes.Y && col == eyes.X:
+				line.WriteString("{{.Eyes}}")
+				continue
+			case row == eyes.Y && col == eyes.X+1:
+				continue
+			}
+
+			x, y := col*cw, row*ch
+			if style == StyleBraille {
+				var dots rune
+				for dy := range ch {
+					for dx := range cw {
+						if ink(x+dx, y+dy) {
+							dots |= brailleDots[dy][dx]
+						}
+					}
+				}
+				if dots == 0 {
+					line.WriteString(" ")
+				} else {
+					line.WriteRune(0x2800 + dots)
+				}
+				continue
+			}
+			i := 0
+			if ink(x, y) {
+				i |= 1
+			}
+			if ink(x, y+1) {
+				i |= 2
+			}
+			line.WriteString(halfBlocks[i])
+		}
+		sb.WriteString(strings.TrimRight(line.String(), " ") + "\n")
+	}
+	return sb.String()
+}
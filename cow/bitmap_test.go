@@ -0,0 +1,67 @@
+package cow
+
+import (
+	"image"
+	"strings"
+	"testing"
+)
+
+func TestBitmapCow_Template(t *testing.T) {
+	b := bitmapCow{
+		Pixels: []string{
+			"##..#...",
+			"#...#...",
+			"........",
+			"..##...#",
+		},
+		Eyes:   image.Pt(4, 2),
+		Indent: 1,
+	}
+
+	tests := []struct {
+		style string
+		want  string
+	}{
+		{StyleHalfBlock, " {{.Thoughts}}\n  {{.Thoughts}}\n█▀  █\n  ▄▄{{.Eyes}} ▄\n"},
+		{StyleBraille, " {{.Thoughts}}\n  {{.Thoughts}}\n⠋⣀{{.Eyes}}\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.style, func(t *testing.T) {
+			if got := b.template(tt.style); got != tt.want {
+				t.Errorf("template(%s) =\n%s\nwant\n%s", tt.style, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRender_BitmapCows(t *testing.T) {
+	for _, name := range []string{"cow-hd", "cow-braille"} {
+		t.Run(name, func(t *testing.T) {
+			got := Render([]string{"moo"}, name, "dead", ActionThink, 40)
+			if !strings.Contains(got, "xx") {
+				t.Errorf("Render() has no mood eyes:\n%s", got)
+			}
+			if !strings.Contains(got, "    o\n     o\n") {
+				t.Errorf("Render() has no thoughts connector:\n%s", got)
+			}
+		})
+	}
+}
+
+func TestRenderer_Color(t *testing.T) {
+	got := (&Renderer{Format: FormatANSI, Color: "red"}).Render([]string{"moo"}, "default", "", ActionSay, 40)
+	if !strings.Contains(got, "\x1b[31m        \\   ^__^\x1b[0m\n") {
+		t.Errorf("Render() did not color the cow:\n%q", got)
+	}
+	if strings.Contains(got, "\x1b[31m _____") {
+		t.Errorf("Render() colored the balloon:\n%q", got)
+	}
+
+	plain := (&Renderer{Color: "red"}).Render([]string{"moo"}, "default", "", ActionSay, 40)
+	if strings.Contains(plain, "\x1b") {
+		t.Errorf("Render() colored plain text:\n%q", plain)
+	}
+	if !ColorExists("bright-blue") || ColorExists("mauve") || len(Colors()) != 16 {
+		t.Errorf("Colors() = %v", Colors())
+	}
+}
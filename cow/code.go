@@ -0,0 +1,118 @@
+package cow
+
+import (
+	"strings"
+
+	"github.com/vnykmshr/gowsay/highlight"
+)
+
+// fence opens and closes code blocks
+const fence = "```"
+
+// block is a run of message lines: prose, which is wrapped, or a fenced
+// code block, which is kept verbatim
+type block struct {
+	code  bool
+	lang  string
+	lines []string
+}
+
+// parseBlocks splits message lines into prose and fenced code blocks. A
+// fence is a line starting with ``` after at most three spaces; an opening
+// fence may name the language, as in ```go. A block left open runs to the
+// end of the message.
+func parseBlocks(lines []string) []block {
+	var blocks []block
+	var current *block
+	for _, line := range lines {
+		trimmed := strings.TrimLeft(line, " ")
+		isFence := len(line)-len(trimmed) <= 3 && strings.HasPrefix(trimmed, fence)
+
+		switch {
+		case current != nil && current.code && isFence && strings.TrimSpace(trimmed) == fence:
+			current = nil
+			continue
+		case (current == nil || !current.code) && isFence:
+			info := strings.Fields(strings.TrimPrefix(trimmed, fence))
+			blocks = append(blocks, block{code: true})
+			if len(info) > 0 {
+				blocks[len(blocks)-1].lang = info[0]
+			}
+			current = &blocks[len(blocks)-1]
+			continue
+		case current == nil:
+			blocks = append(blocks, block{})
+			current = &blocks[len(blocks)-1]
+		}
+		current.lines = append(current.lines, line)
+	}
+	return blocks
+}
+
+// messageLines splits message text into lines of spans: prose run through
+// the filters and wrapped to the column width with inline markdown, and
+// code blocks verbatim, highlighted unless the format is plain text
+func messageLines(text []string, columns int, format Format, filters []Filter) [][]span {
+	if len(text) == 0 {
+		return nil
+	}
+
+	var lines [][]span
+	for _, b := range parseBlocks(strings.Split(strings.Join(text, "\n"), "\n")) {
+		if !b.code {
+			for i, line := range b.lines {
+				b.lines[i] = ApplyFilters(line, filters)
+			}
+			lines = append(lines, proseLines(b.lines, columns, format)...)
+			continue
+		}
+		if len(b.lines) == 0 {
+			continue
+		}
+
+		code := strings.ReplaceAll(strings.Join(b.lines, "\n"), "\t", "        ")
+		lang := b.lang
+		if format == FormatText || format == "" {
+			lang = ""
+		}
+		for _, tokens := range highlight.Lines(highlight.Highlight(lang, code)) {
+			line := make([]span, len(tokens))
+			for i, tok := range tokens {
+				line[i] = span{text: tok.Text, kind: tok.Kind}
+			}
+			lines = append(lines, trimSpans(line))
+		}
+	}
+	return lines
+}
+
+// plainLines turns lines of text into lines of plain spans
+func plainLines(text []string) [][]span {
+	lines := make([][]span, len(text))
+	for i, line := range text {
+		lines[i] = []span{{text: line}}
+	}
+	return lines
+}
+
+// trimSpans removes trailing whitespace from a line of spans
+func trimSpans(line []span) []span {
+	for len(line) > 0 {
+		last := &line[len(line)-1]
+		last.text = strings.TrimRight(last.text, " \r")
+		if last.text != "" {
+			break
+		}
+		line = line[:len(line)-1]
+	}
+	return line
+}
+
+// spanText returns the text of a line of spans, without formatting
+func spanText(line []span) string {
+	var sb strings.Builder
+	for _, s := range line {
+		sb.WriteString(s.text)
+	}
+	return sb.String()
+}
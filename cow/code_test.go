@@ -0,0 +1,119 @@
+package cow
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseBlocks(t *testing.T) {
+	tests := []struct {
+		name  string
+		lines []string
+		want  []block
+	}{
+		{"prose", []string{"hello", "world"}, []block{{lines: []string{"hello", "world"}}}},
+		{
+			"code between prose",
+			[]string{"look:", "```go", "func main() {", "\tmoo()", "}", "```", "neat"},
+			[]block{
+				{lines: []string{"look:"}},
+				{code: true, lang: "go", lines: []string{"func main() {", "\tmoo()", "}"}},
+				{lines: []string{"neat"}},
+			},
+		},
+		{
+			"unclosed fence runs to the end",
+			[]string{"```", "  indented", "", "kept"},
+			[]block{{code: true, lines: []string{"  indented", "", "kept"}}},
+		},
+		{
+			"indented fences and info strings",
+			[]string{"   ```yaml title=herd", "a: 1", "   ```  ", "    ```not a fence"},
+			[]block{
+				{code: true, lang: "yaml", lines: []string{"a: 1"}},
+				{lines: []string{"    ```not a fence"}},
+			},
+		},
+		{
+			"fences with text are not closing",
+			[]string{"```sh", "echo ```x", "```sh", "```"},
+			[]block{{code: true, lang: "sh", lines: []string{"echo ```x", "```sh"}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseBlocks(tt.lines); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseBlocks() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderer_CodeBlock(t *testing.T) {
+	text := []string{
+		"this prose is long enough that it has to wrap",
+		"```go",
+		"func main() {",
+		"\tif moo := true; moo {",
+		"\t\tprintln(\"a very long line that is not wrapped at all\")",
+		"\t}",
+		"}",
+		"```",
+	}
+
+	got := (&Renderer{}).Render(text, "default", "", ActionSay, 20)
+	for _, want := range []string{
+		"/ this prose is long ",
+		"| enough that it has ",
+		"| func main() {",
+		"|         if moo := true; moo {",
+		`|                 println("a very long line that is not wrapped at all") |`,
+		"\\ }",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Render() missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "```") || strings.Contains(got, "\x1b[") {
+		t.Errorf("fences should be removed and text output uncolored:\n%s", got)
+	}
+
+	ansi := (&Renderer{Format: FormatANSI}).Render(text, "default", "", ActionSay, 20)
+	if !strings.Contains(ansi, "\x1b[35mfunc\x1b[0m main() {") || !strings.Contains(ansi, "\x1b[32m\"a very long") {
+		t.Errorf("ANSI output should highlight the code:\n%s", ansi)
+	}
+	// Balloon borders line up once colors are removed
+	plain := strings.NewReplacer("\x1b[0m", "", "\x1b[32m", "", "\x1b[33m", "", "\x1b[35m", "").Replace(ansi)
+	if plain != got {
+		t.Errorf("ANSI output without colors =\n%s\nwant\n%s", plain, got)
+	}
+}
+
+func TestRenderer_CodeBlockFilters(t *testing.T) {
+	text := []string{"run this", "```go", "fmt.Println(\"moo\")", "```", "then relax"}
+	got := (&Renderer{Filters: []Filter{FilterFunc(strings.ToUpper)}}).Render(text, "default", "", ActionSay, 40)
+	for _, want := range []string{"RUN THIS", `fmt.Println("moo")`, "THEN RELAX"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Render() missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "```") || strings.Contains(got, "PRINTLN") {
+		t.Errorf("filters should leave the code block and its fences alone:\n%s", got)
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	for _, name := range append(Formats(), "", "ANSI") {
+		if _, err := ParseFormat(name); err != nil {
+			t.Errorf("ParseFormat(%q) error = %v", name, err)
+		}
+	}
+	if f, _ := ParseFormat(""); f != FormatText {
+		t.Errorf("ParseFormat(\"\") = %s, want %s", f, FormatText)
+	}
+	if _, err := ParseFormat("svg"); err == nil {
+		t.Error("ParseFormat(svg) should fail")
+	}
+}
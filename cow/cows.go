@@ -1,11 +1,10 @@
 package cow
 
-import "math/rand"
-
 var cows map[string]string
 
 var cowNames = []string{
-	"apt", "beavis.zen", "bong", "bud-frogs", "bunny", "calvin", "cheese", "cock", "cower",
+	"apt", "beavis.zen", "bong", "bud-frogs", "bunny", "calvin", "cheese", "cock", "cow-braille",
+	"cow-hd", "cower",
 	"daemon", "default", "dragon", "dragon-and-cow", "duck", "elephant", "elephant-in-snake",
 	"eyes", "flaming-sheep", "ghostbusters", "gnu", "head-in", "hellokitty", "kitty",
 	"koala", "kosh", "luke-koala", "mech-and-cow", "meow", "milk", "moofasa", "moose",
@@ -142,6 +141,10 @@ func init() {
    /\     /\
 `
 
+	cows["cow-braille"] = bitmapCows["cow"].template(StyleBraille)
+
+	cows["cow-hd"] = bitmapCows["cow"].template(StyleHalfBlock)
+
 	cows["cower"] = `     {{.Thoughts}}
       {{.Thoughts}}
         ,__, |    |
@@ -176,7 +179,7 @@ func init() {
 
 	cows["default"] = `        {{.Thoughts}}   ^__^
          {{.Thoughts}}  ({{.Eyes}})\_______
-            (__)\       )\/\
+            (__)\       ){{.Tail}}
              {{.Tongue}} ||----w |
                 ||     ||
 `
@@ -747,7 +750,7 @@ Elephant inside ASCII snake
 
 // RandomCow returns a random cow name
 func RandomCow() string {
-	return cowNames[rand.Intn(len(cowNames))]
+	return Selector{}.RandomCow()
 }
 
 // List returns a sorted list of all available cow names
@@ -762,3 +765,9 @@ func Exists(name string) bool {
 	_, ok := cows[name]
 	return ok
 }
+
+// Template returns the Go template source of the named cow
+func Template(name string) (string, bool) {
+	src, ok := cows[name]
+	return src, ok
+}
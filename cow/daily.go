@@ -0,0 +1,50 @@
+package cow
+
+import (
+	"hash/fnv"
+	"time"
+)
+
+// dateLayout is the format of Daily.Date
+const dateLayout = "2006-01-02"
+
+// Daily is the cow of the day and its message of the day
+type Daily struct {
+	Date      string `json:"date"`
+	Namespace string `json:"namespace,omitempty"`
+	Cow       string `json:"cow"`
+	Message   string `json:"message"`
+}
+
+// Daily picks the cow and message of the day for the given calendar day.
+// The pick depends only on the date, the namespace and the selector's
+// filters, so everyone asking on the same day sees the same cow. Namespaces
+// let separate teams get their own cow of the day.
+func (s Selector) Daily(day time.Time, namespace string) Daily {
+	date := day.Format(dateLayout)
+
+	s.Rand = NewRand(dailySeed(date, namespace))
+	s.History = nil
+
+	return Daily{
+		Date:      date,
+		Namespace: namespace,
+		Cow:       s.RandomCow(),
+		Message:   s.RandomMessage(),
+	}
+}
+
+// NextDay returns the midnight that ends the calendar day of t, in t's location
+func NextDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d+1, 0, 0, 0, 0, t.Location())
+}
+
+// dailySeed derives a stable seed from the date and namespace
+func dailySeed(date, namespace string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte(date))
+	h.Write([]byte{0})
+	h.Write([]byte(namespace))
+	return int64(h.Sum64())
+}
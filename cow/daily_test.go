@@ -0,0 +1,52 @@
+package cow
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSelector_Daily(t *testing.T) {
+	day := time.Date(2026, 10, 19, 9, 30, 0, 0, time.UTC)
+	later := time.Date(2026, 10, 19, 23, 59, 0, 0, time.UTC)
+
+	a := Selector{}.Daily(day, "")
+	b := Selector{Rand: NewRand(1)}.Daily(later, "")
+	if a != b {
+		t.Errorf("same day picked %+v and %+v", a, b)
+	}
+	if a.Date != "2026-10-19" {
+		t.Errorf("Date = %s, want 2026-10-19", a.Date)
+	}
+	if !Exists(a.Cow) || a.Message == "" {
+		t.Errorf("Daily() = %+v, want a cow and a message", a)
+	}
+
+	// Different days and namespaces should not all pick the same cow
+	seen := map[string]bool{}
+	for i := 0; i < 30; i++ {
+		seen[Selector{}.Daily(day.AddDate(0, 0, i), "").Cow] = true
+		seen[Selector{}.Daily(day, string(rune('a'+i))).Cow] = true
+	}
+	if len(seen) < 5 {
+		t.Errorf("only %d different cows over 60 days and namespaces", len(seen))
+	}
+}
+
+func TestSelector_DailySafeMode(t *testing.T) {
+	sel := Selector{SafeMode: true}
+	day := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 365; i++ {
+		if d := sel.Daily(day.AddDate(0, 0, i), ""); !IsSafe(d.Cow) {
+			t.Fatalf("safe mode daily cow on %s is %s", d.Date, d.Cow)
+		}
+	}
+}
+
+func TestNextDay(t *testing.T) {
+	tokyo := time.FixedZone("JST", 9*60*60)
+	got := NextDay(time.Date(2026, 12, 31, 18, 0, 0, 0, tokyo))
+	want := time.Date(2027, 1, 1, 0, 0, 0, 0, tokyo)
+	if !got.Equal(want) {
+		t.Errorf("NextDay() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,117 @@
+package cow
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Filter transforms message text before it is wrapped into the balloon.
+// Register custom filters with RegisterFilter to make them selectable by
+// name in the CLI, the API and Slack.
+type Filter interface {
+	Apply(text string) string
+}
+
+// FilterFunc adapts an ordinary function to the Filter interface
+type FilterFunc func(text string) string
+
+// Apply calls f(text)
+func (f FilterFunc) Apply(text string) string {
+	return f(text)
+}
+
+// Built-in filter names
+const (
+	FilterUpper    = "upper"
+	FilterMoo      = "moo"
+	FilterPigLatin = "piglatin"
+	FilterLeet     = "leet"
+	FilterReverse  = "reverse"
+	FilterROT13    = "rot13"
+	FilterRedact   = "redact"
+)
+
+var (
+	filtersMu sync.RWMutex
+	filters   = map[string]Filter{
+		FilterUpper:    FilterFunc(strings.ToUpper),
+		FilterMoo:      FilterFunc(mooify),
+		FilterPigLatin: FilterFunc(pigLatin),
+		FilterLeet:     FilterFunc(leet),
+		FilterReverse:  FilterFunc(reverse),
+		FilterROT13:    FilterFunc(rot13),
+		FilterRedact:   FilterFunc(redact),
+	}
+)
+
+// RegisterFilter makes a filter available by name. It panics if the name
+// is empty, contains a comma, or is already registered.
+func RegisterFilter(name string, f Filter) {
+	if name == "" || strings.ContainsAny(name, ", ") {
+		panic(fmt.Sprintf("cow: invalid filter name %q", name))
+	}
+	if f == nil {
+		panic("cow: RegisterFilter filter is nil")
+	}
+
+	filtersMu.Lock()
+	defer filtersMu.Unlock()
+	if _, dup := filters[name]; dup {
+		panic("cow: RegisterFilter called twice for filter " + name)
+	}
+	filters[name] = f
+}
+
+// GetFilter returns the filter registered under name
+func GetFilter(name string) (Filter, bool) {
+	filtersMu.RLock()
+	defer filtersMu.RUnlock()
+	f, ok := filters[name]
+	return f, ok
+}
+
+// ListFilters returns the names of all registered filters, sorted
+func ListFilters() []string {
+	filtersMu.RLock()
+	defer filtersMu.RUnlock()
+	names := make([]string, 0, len(filters))
+	for name := range filters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// UnknownFilterError is returned by ParseFilters for a filter name that is
+// not registered
+type UnknownFilterError struct {
+	Name string
+}
+
+func (e *UnknownFilterError) Error() string {
+	return fmt.Sprintf("filter '%s' not found", e.Name)
+}
+
+// ParseFilters looks up a comma-separated chain of filter names, such as
+// "upper,moo". Filters are applied in the order given.
+func ParseFilters(s string) ([]Filter, error) {
+	var chain []Filter
+	for _, name := range ParseList(s) {
+		f, ok := GetFilter(name)
+		if !ok {
+			return nil, &UnknownFilterError{Name: name}
+		}
+		chain = append(chain, f)
+	}
+	return chain, nil
+}
+
+// ApplyFilters runs text through the filters in order
+func ApplyFilters(text string, chain []Filter) string {
+	for _, f := range chain {
+		text = f.Apply(text)
+	}
+	return text
+}
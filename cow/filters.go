@@ -0,0 +1,161 @@
+package cow
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// word matches words for the word-based filters, keeping apostrophes
+var word = regexp.MustCompile(`[A-Za-z]+(?:'[A-Za-z]+)*`)
+
+// mooWords maps words to their cow puns
+var mooWords = map[string]string{
+	"hello":     "hay",
+	"hey":       "hay",
+	"hi":        "moo",
+	"you":       "moo",
+	"forever":   "for-heifer",
+	"other":     "udder",
+	"others":    "udders",
+	"another":   "an-udder",
+	"utterly":   "udderly",
+	"heard":     "herd",
+	"legendary": "legen-dairy",
+	"very":      "dairy",
+	"news":      "moos",
+	"move":      "moove",
+	"moved":     "mooved",
+	"movie":     "moovie",
+	"movies":    "moovies",
+	"music":     "moosic",
+	"museum":    "mooseum",
+	"amazing":   "amoozing",
+	"marvelous": "moovelous",
+	"moment":    "mooment",
+	"mood":      "moood",
+	"smooth":    "smoooth",
+	"vu":        "moo",
+	"nice":      "moo-tiful",
+	"great":     "grazing",
+}
+
+// mooify swaps words for cow puns
+func mooify(text string) string {
+	return word.ReplaceAllStringFunc(text, func(w string) string {
+		pun, ok := mooWords[strings.ToLower(w)]
+		if !ok {
+			return w
+		}
+		return matchCase(pun, w)
+	})
+}
+
+// pigLatin moves each word's leading consonants to the end and adds "ay",
+// or adds "way" to words starting with a vowel
+func pigLatin(text string) string {
+	return word.ReplaceAllStringFunc(text, func(w string) string {
+		lower := strings.ToLower(w)
+		split := strings.IndexFunc(lower, func(r rune) bool { return strings.ContainsRune("aeiou", r) })
+		switch {
+		case split == 0:
+			return matchCase(lower+"way", w)
+		case split < 0:
+			// No vowel: "y" acts as one ("rhythm" -> "ythmrhay")
+			if split = strings.IndexRune(lower[1:], 'y') + 1; split == 0 {
+				return w
+			}
+		case lower[split] == 'u' && lower[split-1] == 'q':
+			split++
+		}
+		return matchCase(lower[split:]+lower[:split]+"ay", w)
+	})
+}
+
+// matchCase applies the capitalization of like to s: all upper case,
+// capitalized, or lower case
+func matchCase(s, like string) string {
+	switch {
+	case len(like) > 1 && like == strings.ToUpper(like):
+		return strings.ToUpper(s)
+	case unicode.IsUpper(rune(like[0])):
+		return strings.ToUpper(s[:1]) + s[1:]
+	}
+	return s
+}
+
+var leetReplacer = strings.NewReplacer(
+	"a", "4", "A", "4",
+	"e", "3", "E", "3",
+	"i", "1", "I", "1",
+	"o", "0", "O", "0",
+	"s", "5", "S", "5",
+	"t", "7", "T", "7",
+)
+
+// leet writes text in leetspeak
+func leet(text string) string {
+	return leetReplacer.Replace(text)
+}
+
+// reverse reverses text, keeping combining marks on their base characters
+func reverse(text string) string {
+	var clusters [][]rune
+	for _, r := range text {
+		if len(clusters) > 0 && unicode.In(r, unicode.Mn, unicode.Me) {
+			clusters[len(clusters)-1] = append(clusters[len(clusters)-1], r)
+			continue
+		}
+		clusters = append(clusters, []rune{r})
+	}
+	slices.Reverse(clusters)
+	return string(slices.Concat(clusters...))
+}
+
+// rot13 rotates ASCII letters by 13 places
+func rot13(text string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return 'a' + (r-'a'+13)%26
+		case r >= 'A' && r <= 'Z':
+			return 'A' + (r-'A'+13)%26
+		}
+		return r
+	}, text)
+}
+
+// redacted replaces secrets found by redact
+const redacted = "[REDACTED]"
+
+// secrets match things that look like secrets or tokens. A match's first
+// group, if any, is kept, so "password=hunter2" becomes
+// "password=[REDACTED]".
+var secrets = []*regexp.Regexp{
+	regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----[\s\S]*?(?:-----END [A-Z ]*PRIVATE KEY-----|$)`),
+	regexp.MustCompile(`(?i)(\bbearer\s+)[A-Za-z0-9._~+/=-]+`),
+	regexp.MustCompile(`(?i)(\b(?:password|passwd|pwd|secret|token|api[_-]?key|access[_-]?key|client[_-]?secret)\b["']?\s*[:=]\s*)["']?[^\s"',;]+`),
+	regexp.MustCompile(`\bxox[abposr]-[A-Za-z0-9-]{10,}`),
+	regexp.MustCompile(`\b(?:gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{22,})`),
+	regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`),
+	regexp.MustCompile(`\beyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+`),
+	regexp.MustCompile(`\bsk-[A-Za-z0-9_-]{20,}`),
+}
+
+// longToken matches long runs of token characters, which are redacted if
+// they mix letters and digits, like API keys and hashes do
+var longToken = regexp.MustCompile(`[A-Za-z0-9+/_=-]{32,}`)
+
+// redact replaces anything that looks like a secret or token
+func redact(text string) string {
+	for _, re := range secrets {
+		text = re.ReplaceAllString(text, "${1}"+redacted)
+	}
+	return longToken.ReplaceAllStringFunc(text, func(s string) string {
+		if strings.ContainsFunc(s, unicode.IsDigit) && strings.ContainsFunc(s, unicode.IsLetter) {
+			return redacted
+		}
+		return s
+	})
+}
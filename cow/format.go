@@ -0,0 +1,121 @@
+package cow
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/vnykmshr/gowsay/highlight"
+)
+
+// Format is an output format for rendered text
+type Format string
+
+// Output formats: plain text, or text with ANSI colors for terminals
+const (
+	FormatText Format = "text"
+	FormatANSI Format = "ansi"
+)
+
+// Formats returns the names of the output formats
+func Formats() []string {
+	return []string{string(FormatText), string(FormatANSI)}
+}
+
+// ParseFormat looks up an output format by name. An empty name is
+// FormatText.
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(strings.ToLower(s)); f {
+	case "":
+		return FormatText, nil
+	case FormatText, FormatANSI:
+		return f, nil
+	}
+	return "", fmt.Errorf("format '%s' not found", s)
+}
+
+// span is a run of message text with a highlight kind and inline style
+type span struct {
+	text  string
+	kind  highlight.Kind
+	style style
+}
+
+// ansiColors are the SGR parameters for each kind of highlighted token
+var ansiColors = map[highlight.Kind]string{
+	highlight.Keyword:  "35",
+	highlight.Type:     "36",
+	highlight.String:   "32",
+	highlight.Number:   "33",
+	highlight.Literal:  "33",
+	highlight.Comment:  "90",
+	highlight.Key:      "34",
+	highlight.Variable: "36",
+}
+
+// cowColors are the SGR parameters for the colors a cow can be drawn in
+var cowColors = map[string]string{
+	"black": "30", "red": "31", "green": "32", "yellow": "33",
+	"blue": "34", "magenta": "35", "cyan": "36", "white": "37", "gray": "90",
+	"bright-red": "91", "bright-green": "92", "bright-yellow": "93",
+	"bright-blue": "94", "bright-magenta": "95", "bright-cyan": "96", "bright-white": "97",
+}
+
+// Colors returns the names of the colors a cow can be drawn in
+func Colors() []string {
+	return slices.Sorted(maps.Keys(cowColors))
+}
+
+// ColorExists reports whether a cow can be drawn in the named color
+func ColorExists(name string) bool {
+	_, ok := cowColors[name]
+	return ok
+}
+
+// colorLines wraps each line of text that is not blank in an SGR color
+func colorLines(s, sgr string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if strings.TrimSpace(line) != "" {
+			lines[i] = "\x1b[" + sgr + "m" + line + "\x1b[0m"
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// ansiStyles are the SGR parameters for inline styles
+var ansiStyles = []struct {
+	style style
+	sgr   string
+}{{styleBold, "1"}, {styleItalic, "3"}, {styleStrike, "9"}}
+
+// span formats a span of text: with SGR attributes and colors for ANSI,
+// or as is with markdown markers dropped
+func (f Format) span(s span) string {
+	if f == FormatANSI {
+		var sgr []string
+		for _, a := range ansiStyles {
+			if s.style&a.style != 0 {
+				sgr = append(sgr, a.sgr)
+			}
+		}
+		if s.kind != highlight.Plain {
+			sgr = append(sgr, ansiColors[s.kind])
+		}
+		if len(sgr) == 0 {
+			return s.text
+		}
+		return "\x1b[" + strings.Join(sgr, ";") + "m" + s.text + "\x1b[0m"
+	}
+	return s.text
+}
+
+// bullet returns the bullet for list items: a dot when styled, a dash in
+// plain text
+func (f Format) bullet() string {
+	if f == FormatANSI {
+		return "•"
+	}
+	return "-"
+}
@@ -0,0 +1,39 @@
+package cow
+
+import "sync"
+
+// History remembers the last few cows picked for each key, such as a Slack
+// user or channel, so a Selector can avoid repeating them. It is safe for
+// concurrent use.
+type History struct {
+	mu     sync.Mutex
+	window int
+	recent map[string][]string
+}
+
+// NewHistory returns a history that remembers the last window picks per key
+func NewHistory(window int) *History {
+	return &History{window: window, recent: make(map[string][]string)}
+}
+
+// Recent returns the most recent picks for key, oldest first
+func (h *History) Recent(key string) []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]string(nil), h.recent[key]...)
+}
+
+// Add records a pick for key, forgetting the oldest one beyond the window
+func (h *History) Add(key, name string) {
+	if h.window <= 0 {
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	recent := append(h.recent[key], name)
+	if len(recent) > h.window {
+		recent = recent[len(recent)-h.window:]
+	}
+	h.recent[key] = recent
+}
@@ -0,0 +1,156 @@
+package cow
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	runewidth "github.com/mattn/go-runewidth"
+	"github.com/mitchellh/go-wordwrap"
+)
+
+// style is a set of inline text attributes set by markdown markers
+type style uint8
+
+const (
+	styleBold style = 1 << iota
+	styleItalic
+	styleStrike
+)
+
+// markers maps inline markdown markers to their styles: *bold*, _italic_
+// and ~strike~. Doubled markers (**bold**) work too.
+var markers = map[byte]style{'*': styleBold, '_': styleItalic, '~': styleStrike}
+
+// bullet matches a list item: an indent, a bullet and the item text
+var bullet = regexp.MustCompile(`^( *)[-*+•] +(.*)$`)
+
+// proseLines wraps lines of prose to the column width, styling inline
+// markdown and indenting wrapped list items under their text
+func proseLines(text []string, columns int, format Format) [][]span {
+	var lines [][]span
+	for _, line := range text {
+		line = strings.ReplaceAll(line, "\t", "        ")
+
+		m := bullet.FindStringSubmatch(line)
+		if m == nil {
+			lines = append(lines, wrapSpans(parseInline(line, 0), columns)...)
+			continue
+		}
+
+		prefix := m[1] + format.bullet() + " "
+		indent := runewidth.StringWidth(prefix)
+		for i, item := range wrapSpans(parseInline(m[2], 0), max(columns-indent, 1)) {
+			lead := span{text: prefix}
+			if i > 0 {
+				lead.text = strings.Repeat(" ", indent)
+			}
+			lines = append(lines, append([]span{lead}, item...))
+		}
+	}
+	return lines
+}
+
+// parseInline splits a line into spans styled by inline markdown markers.
+// A marker opens at the start of a word and closes at the end of one, so
+// snake_case and 2*3*4 stay as they are; markers without a match are
+// kept as text.
+func parseInline(s string, st style) []span {
+	var spans []span
+	start := 0
+	for i := 0; i < len(s); i++ {
+		m, ok := markers[s[i]]
+		if !ok || wordBefore(s, i) {
+			continue
+		}
+		delim := s[i : i+1]
+		if strings.HasPrefix(s[i+1:], delim) {
+			delim += delim
+		}
+		open := i + len(delim)
+		if open == len(s) || s[open] == ' ' || s[open] == s[i] {
+			continue
+		}
+		end := closing(s, open, delim)
+		if end < 0 {
+			continue
+		}
+
+		if start < i {
+			spans = append(spans, span{text: s[start:i], style: st})
+		}
+		spans = append(spans, parseInline(s[open:end], st|m)...)
+		i = end + len(delim) - 1
+		start = end + len(delim)
+	}
+	if start < len(s) {
+		spans = append(spans, span{text: s[start:], style: st})
+	}
+	return spans
+}
+
+// closing returns the index of the marker closing delim at or after i, or
+// -1 if there is none
+func closing(s string, i int, delim string) int {
+	for j := i + 1; j+len(delim) <= len(s); j++ {
+		if !strings.HasPrefix(s[j:], delim) || s[j-1] == ' ' {
+			continue
+		}
+		after := j + len(delim)
+		if after == len(s) || s[after] != delim[0] && !wordAt(s, after) {
+			return j
+		}
+	}
+	return -1
+}
+
+// wordBefore reports whether the character before i is a letter or digit
+func wordBefore(s string, i int) bool {
+	if i == 0 {
+		return false
+	}
+	r, _ := utf8.DecodeLastRuneInString(s[:i])
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// wordAt reports whether the character at i is a letter or digit
+func wordAt(s string, i int) bool {
+	r, _ := utf8.DecodeRuneInString(s[i:])
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// wrapSpans word wraps a line of spans to the column width, keeping the
+// style of each character
+func wrapSpans(spans []span, columns int) [][]span {
+	var styles []style
+	for _, s := range spans {
+		for range s.text {
+			styles = append(styles, s.style)
+		}
+	}
+	in := []rune(spanText(spans))
+
+	// The wrapped text is the input with line breaks added and the spaces
+	// around them dropped, so each character lines up with one of the input
+	var lines [][]span
+	var line []span
+	i := 0
+	for _, c := range wordwrap.WrapString(string(in), uint(columns)) {
+		if c == '\n' {
+			lines = append(lines, line)
+			line = nil
+			continue
+		}
+		for i < len(in) && in[i] != c {
+			i++
+		}
+		if n := len(line); n > 0 && line[n-1].style == styles[i] {
+			line[n-1].text += string(c)
+		} else {
+			line = append(line, span{text: string(c), style: styles[i]})
+		}
+		i++
+	}
+	return append(lines, line)
+}
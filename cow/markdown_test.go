@@ -0,0 +1,110 @@
+package cow
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+// styled writes spans with their styles marked, e.g. "{b:bold} text"
+func styled(spans []span) string {
+	var sb strings.Builder
+	for _, s := range spans {
+		var marks string
+		for _, m := range []struct {
+			style style
+			mark  string
+		}{{styleBold, "b"}, {styleItalic, "i"}, {styleStrike, "s"}} {
+			if s.style&m.style != 0 {
+				marks += m.mark
+			}
+		}
+		if marks == "" {
+			sb.WriteString(s.text)
+			continue
+		}
+		sb.WriteString("{" + marks + ":" + s.text + "}")
+	}
+	return sb.String()
+}
+
+func TestParseInline(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"*bold* _italic_ ~strike~", "{b:bold} {i:italic} {s:strike}"},
+		{"**double** __marks__ ~~too~~", "{b:double} {i:marks} {s:too}"},
+		{"*bold _and italic_ text*", "{b:bold }{bi:and italic}{b: text}"},
+		{"(*quoted*), *two words*.", "({b:quoted}), {b:two words}."},
+		{"snake_case_name and 2*3*4", "snake_case_name and 2*3*4"},
+		{"* not bold * and *unclosed", "* not bold * and *unclosed"},
+		{"*a*b* ok", "{b:a*b} ok"},
+		{"_héllo_ wörld_", "{i:héllo} wörld_"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			if got := styled(parseInline(tt.in, 0)); got != tt.want {
+				t.Errorf("parseInline(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProseLines(t *testing.T) {
+	text := []string{
+		"a *bold* move that wraps",
+		"- first item that wraps",
+		"  * nested _item_ wraps too",
+		"+ short",
+		"-not a bullet",
+	}
+	want := []string{
+		"a {b:bold} move that",
+		"wraps",
+		"- first item that",
+		"  wraps",
+		"  - nested {i:item}",
+		"    wraps too",
+		"- short",
+		"-not a bullet",
+	}
+
+	var got []string
+	for _, line := range proseLines(text, 20, FormatText) {
+		got = append(got, styled(line))
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("proseLines() =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestRenderer_Markdown(t *testing.T) {
+	text := []string{"*ship* _it_ ~now~", "- one"}
+
+	tests := []struct {
+		format Format
+		want   []string
+	}{
+		{FormatText, []string{"/ ship it now \\", "\\ - one       /"}},
+		{FormatANSI, []string{"/ \x1b[1mship\x1b[0m \x1b[3mit\x1b[0m \x1b[9mnow\x1b[0m \\", "\\ • one       /"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			got := (&Renderer{Format: tt.format}).Render(text, "default", "", ActionSay, 40)
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("Render() missing %q:\n%s", want, got)
+				}
+			}
+		})
+	}
+
+	code := []string{"```go", "x := *p", "```"}
+	if got := (&Renderer{}).Render(code, "default", "", ActionSay, 40); !strings.Contains(got, "< x := *p >") {
+		t.Errorf("markdown should not apply in code blocks:\n%s", got)
+	}
+}
@@ -2,6 +2,7 @@ package cow
 
 import (
 	"math/rand"
+	"slices"
 )
 
 var moos []string
@@ -58,3 +59,8 @@ func init() {
 func RandomMessage() string {
 	return moos[rand.Intn(len(moos))]
 }
+
+// Messages returns the built-in moo messages
+func Messages() []string {
+	return slices.Clone(moos)
+}
@@ -0,0 +1,161 @@
+package cow
+
+import (
+	"strings"
+	"sync"
+
+	runewidth "github.com/mattn/go-runewidth"
+)
+
+// Content ratings
+const (
+	RatingSafe = "safe"
+	RatingNSFW = "nsfw"
+)
+
+// Cow tags
+const (
+	TagAnimal  = "animal"
+	TagCartoon = "cartoon"
+	TagFantasy = "fantasy"
+	TagFood    = "food"
+	TagHoliday = "holiday"
+	TagSciFi   = "sci-fi"
+	TagTech    = "tech"
+)
+
+// Cow sources
+const (
+	sourceCowsay    = "cowsay"
+	sourceCommunity = "community"
+	sourceGowsay    = "gowsay"
+)
+
+// Info describes a cow: where it came from, what it shows and how big it is
+type Info struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Author      string   `json:"author"`
+	Tags        []string `json:"tags"`
+	Width       int      `json:"width"`
+	Height      int      `json:"height"`
+	Rating      string   `json:"rating"`
+}
+
+// cowInfo holds the hand-written metadata for each cow. Name, Width and
+// Height are filled in by GetInfo.
+var cowInfo = map[string]Info{
+	"apt":               {Description: "Debian's APT super cow", Author: sourceCommunity, Tags: []string{TagAnimal, TagTech}, Rating: RatingSafe},
+	"beavis.zen":        {Description: "Beavis, reaching enlightenment", Author: sourceCowsay, Tags: []string{TagCartoon}, Rating: RatingSafe},
+	"bong":              {Description: "A cow taking a hit from a bong", Author: sourceCowsay, Tags: []string{TagAnimal}, Rating: RatingNSFW},
+	"bud-frogs":         {Description: "The Budweiser frogs", Author: sourceCowsay, Tags: []string{TagAnimal, TagCartoon}, Rating: RatingSafe},
+	"bunny":             {Description: "A small bunny", Author: sourceCowsay, Tags: []string{TagAnimal}, Rating: RatingSafe},
+	"calvin":            {Description: "Calvin, of Calvin and Hobbes", Author: sourceCommunity, Tags: []string{TagCartoon}, Rating: RatingSafe},
+	"cheese":            {Description: "A wedge of cheese with a face", Author: sourceCowsay, Tags: []string{TagFood}, Rating: RatingSafe},
+	"cock":              {Description: "A rooster", Author: sourceCommunity, Tags: []string{TagAnimal}, Rating: RatingSafe},
+	"cow-braille":       {Description: "A cow drawn in braille dots", Author: sourceGowsay, Tags: []string{TagAnimal}, Rating: RatingSafe},
+	"cow-hd":            {Description: "A cow drawn in half blocks", Author: sourceGowsay, Tags: []string{TagAnimal}, Rating: RatingSafe},
+	"cower":             {Description: "A cow cowering in fear", Author: sourceCowsay, Tags: []string{TagAnimal}, Rating: RatingSafe},
+	"daemon":            {Description: "The BSD daemon", Author: sourceCowsay, Tags: []string{TagFantasy, TagTech}, Rating: RatingSafe},
+	"default":           {Description: "The classic cowsay cow", Author: sourceCowsay, Tags: []string{TagAnimal}, Rating: RatingSafe},
+	"dragon":            {Description: "A fire-breathing dragon", Author: sourceCowsay, Tags: []string{TagFantasy}, Rating: RatingSafe},
+	"dragon-and-cow":    {Description: "A dragon about to roast a cow", Author: sourceCowsay, Tags: []string{TagAnimal, TagFantasy}, Rating: RatingSafe},
+	"duck":              {Description: "A duck", Author: sourceCommunity, Tags: []string{TagAnimal}, Rating: RatingSafe},
+	"elephant":          {Description: "An elephant", Author: sourceCowsay, Tags: []string{TagAnimal}, Rating: RatingSafe},
+	"elephant-in-snake": {Description: "An elephant swallowed by a boa, after The Little Prince", Author: sourceCowsay, Tags: []string{TagAnimal}, Rating: RatingSafe},
+	"eyes":              {Description: "A pair of staring eyes", Author: sourceCowsay, Rating: RatingSafe},
+	"flaming-sheep":     {Description: "A sheep on fire", Author: sourceCowsay, Tags: []string{TagAnimal}, Rating: RatingSafe},
+	"ghostbusters":      {Description: "The Ghostbusters logo", Author: sourceCowsay, Tags: []string{TagSciFi}, Rating: RatingSafe},
+	"gnu":               {Description: "The GNU project's gnu", Author: sourceCommunity, Tags: []string{TagAnimal, TagTech}, Rating: RatingSafe},
+	"head-in":           {Description: "A cow with its head somewhere it shouldn't be", Author: sourceCowsay, Tags: []string{TagAnimal}, Rating: RatingNSFW},
+	"hellokitty":        {Description: "Hello Kitty", Author: sourceCowsay, Tags: []string{TagCartoon}, Rating: RatingSafe},
+	"kitty":             {Description: "A kitten", Author: sourceCowsay, Tags: []string{TagAnimal}, Rating: RatingSafe},
+	"koala":             {Description: "A koala", Author: sourceCowsay, Tags: []string{TagAnimal}, Rating: RatingSafe},
+	"kosh":              {Description: "Ambassador Kosh from Babylon 5", Author: sourceCowsay, Tags: []string{TagSciFi}, Rating: RatingSafe},
+	"luke-koala":        {Description: "A koala dressed as Luke Skywalker", Author: sourceCowsay, Tags: []string{TagAnimal, TagSciFi}, Rating: RatingSafe},
+	"mech-and-cow":      {Description: "A giant mech towering over a cow", Author: sourceCommunity, Tags: []string{TagAnimal, TagSciFi}, Rating: RatingSafe},
+	"meow":              {Description: "A cat", Author: sourceCowsay, Tags: []string{TagAnimal}, Rating: RatingSafe},
+	"milk":              {Description: "A carton of milk", Author: sourceCowsay, Tags: []string{TagFood}, Rating: RatingSafe},
+	"moofasa":           {Description: "Moofasa, the Lion King cow", Author: sourceCowsay, Tags: []string{TagAnimal, TagCartoon}, Rating: RatingSafe},
+	"moose":             {Description: "A moose", Author: sourceCowsay, Tags: []string{TagAnimal}, Rating: RatingSafe},
+	"mutilated":         {Description: "A mutilated cow", Author: sourceCowsay, Tags: []string{TagAnimal}, Rating: RatingNSFW},
+	"pony":              {Description: "A pony", Author: sourceCommunity, Tags: []string{TagAnimal}, Rating: RatingSafe},
+	"pony-smaller":      {Description: "A smaller pony", Author: sourceCommunity, Tags: []string{TagAnimal}, Rating: RatingSafe},
+	"ren":               {Description: "Ren, of Ren & Stimpy", Author: sourceCowsay, Tags: []string{TagCartoon}, Rating: RatingSafe},
+	"sheep":             {Description: "A sheep", Author: sourceCowsay, Tags: []string{TagAnimal}, Rating: RatingSafe},
+	"skeleton":          {Description: "A spooky skeleton", Author: sourceCowsay, Tags: []string{TagHoliday}, Rating: RatingSafe},
+	"snowman":           {Description: "A snowman", Author: sourceCommunity, Tags: []string{TagHoliday}, Rating: RatingSafe},
+	"sodomized-sheep":   {Description: "A sheep in a compromising position", Author: sourceCowsay, Tags: []string{TagAnimal}, Rating: RatingNSFW},
+	"stegosaurus":       {Description: "A stegosaurus", Author: sourceCowsay, Tags: []string{TagAnimal}, Rating: RatingSafe},
+	"stimpy":            {Description: "Stimpy, of Ren & Stimpy", Author: sourceCowsay, Tags: []string{TagCartoon}, Rating: RatingSafe},
+	"suse":              {Description: "The SUSE chameleon", Author: sourceCommunity, Tags: []string{TagAnimal, TagTech}, Rating: RatingSafe},
+	"three-eyes":        {Description: "A cow with three eyes", Author: sourceCowsay, Tags: []string{TagAnimal, TagSciFi}, Rating: RatingSafe},
+	"turkey":            {Description: "A Thanksgiving turkey", Author: sourceCowsay, Tags: []string{TagAnimal, TagHoliday}, Rating: RatingSafe},
+	"turtle":            {Description: "A turtle", Author: sourceCowsay, Tags: []string{TagAnimal}, Rating: RatingSafe},
+	"tux":               {Description: "Tux, the Linux penguin", Author: sourceCowsay, Tags: []string{TagAnimal, TagTech}, Rating: RatingSafe},
+	"unipony":           {Description: "A unicorn pony", Author: sourceCommunity, Tags: []string{TagFantasy}, Rating: RatingSafe},
+	"unipony-smaller":   {Description: "A smaller unicorn pony", Author: sourceCommunity, Tags: []string{TagFantasy}, Rating: RatingSafe},
+	"vader":             {Description: "Cowth Vader", Author: sourceCowsay, Tags: []string{TagSciFi}, Rating: RatingSafe},
+	"vader-koala":       {Description: "A koala dressed as Darth Vader", Author: sourceCowsay, Tags: []string{TagAnimal, TagSciFi}, Rating: RatingSafe},
+	"www":               {Description: "A cow surfing the web", Author: sourceCowsay, Tags: []string{TagTech}, Rating: RatingSafe},
+}
+
+var (
+	dimensionsOnce sync.Once
+	dimensions     map[string][2]int
+)
+
+// GetInfo returns the metadata for the given cow
+func GetInfo(name string) (Info, bool) {
+	info, ok := cowInfo[name]
+	if !ok {
+		return Info{}, false
+	}
+
+	dimensionsOnce.Do(measureCows)
+	info.Name = name
+	info.Width = dimensions[name][0]
+	info.Height = dimensions[name][1]
+	info.Tags = append([]string(nil), info.Tags...)
+	return info, true
+}
+
+// ListInfo returns the metadata for all available cows, in the order of List
+func ListInfo() []Info {
+	result := make([]Info, 0, len(cowNames))
+	for _, name := range cowNames {
+		if info, ok := GetInfo(name); ok {
+			result = append(result, info)
+		}
+	}
+	return result
+}
+
+// HasTag reports whether the cow carries the given tag
+func (i Info) HasTag(tag string) bool {
+	for _, t := range i.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// measureCows renders every cow with the default face and records its
+// display width and height
+func measureCows() {
+	dimensions = make(map[string][2]int, len(cows))
+	for name := range cows {
+		face := newFace(name, "")
+		face.Thoughts = "\\"
+		art := renderCow(face)
+		lines := strings.Split(strings.TrimRight(art, "\n"), "\n")
+		width := 0
+		for _, line := range lines {
+			if w := runewidth.StringWidth(line); w > width {
+				width = w
+			}
+		}
+		dimensions[name] = [2]int{width, len(lines)}
+	}
+}
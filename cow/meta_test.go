@@ -0,0 +1,70 @@
+package cow
+
+import "testing"
+
+func TestGetInfo(t *testing.T) {
+	info, ok := GetInfo("default")
+	if !ok {
+		t.Fatal("GetInfo(default) not found")
+	}
+	if info.Name != "default" {
+		t.Errorf("Name = %q, want default", info.Name)
+	}
+	if info.Rating != RatingSafe {
+		t.Errorf("Rating = %q, want %q", info.Rating, RatingSafe)
+	}
+	if !info.HasTag(TagAnimal) {
+		t.Errorf("default cow should be tagged %q, got %v", TagAnimal, info.Tags)
+	}
+	if info.Width != 28 || info.Height != 5 {
+		t.Errorf("dimensions = %dx%d, want 28x5", info.Width, info.Height)
+	}
+
+	if _, ok := GetInfo("nonexistent"); ok {
+		t.Error("GetInfo(nonexistent) should not be found")
+	}
+}
+
+func TestGetInfo_Rating(t *testing.T) {
+	for _, name := range []string{"sodomized-sheep", "mutilated", "bong"} {
+		info, ok := GetInfo(name)
+		if !ok {
+			t.Fatalf("GetInfo(%s) not found", name)
+		}
+		if info.Rating != RatingNSFW {
+			t.Errorf("%s rating = %q, want %q", name, info.Rating, RatingNSFW)
+		}
+	}
+}
+
+func TestListInfo(t *testing.T) {
+	infos := ListInfo()
+	if len(infos) != len(List()) {
+		t.Fatalf("ListInfo() returned %d cows, List() has %d", len(infos), len(List()))
+	}
+
+	for _, info := range infos {
+		if info.Description == "" {
+			t.Errorf("%s has no description", info.Name)
+		}
+		if info.Author == "" {
+			t.Errorf("%s has no author", info.Name)
+		}
+		if info.Rating != RatingSafe && info.Rating != RatingNSFW {
+			t.Errorf("%s has unknown rating %q", info.Name, info.Rating)
+		}
+		if Exists(info.Name) && (info.Width == 0 || info.Height == 0) {
+			t.Errorf("%s has no dimensions", info.Name)
+		}
+	}
+}
+
+func TestGetInfo_TagsAreCopied(t *testing.T) {
+	info, _ := GetInfo("tux")
+	info.Tags[0] = "mutated"
+
+	again, _ := GetInfo("tux")
+	if again.Tags[0] == "mutated" {
+		t.Error("GetInfo should return a copy of the tags")
+	}
+}
@@ -1,22 +1,23 @@
 package cow
 
-import "math/rand"
-
-// Mood represents a facial expression configuration
+// Mood represents a facial expression configuration. Blink is the eyes
+// when closed in animations, "--" if empty.
 type Mood struct {
 	Eyes   string
 	Tongue string
+	Blink  string
+	Rating string
 }
 
 var moods = map[string]Mood{
-	"borg":     {Eyes: "==", Tongue: "  "},
-	"dead":     {Eyes: "xx", Tongue: "U "},
-	"greedy":   {Eyes: "$$", Tongue: "  "},
-	"paranoid": {Eyes: "@@", Tongue: "  "},
-	"stoned":   {Eyes: "**", Tongue: "U "},
-	"tired":    {Eyes: "--", Tongue: "  "},
-	"wired":    {Eyes: "OO", Tongue: "  "},
-	"young":    {Eyes: "..", Tongue: "  "},
+	"borg":     {Eyes: "==", Tongue: "  ", Rating: RatingSafe},
+	"dead":     {Eyes: "xx", Tongue: "U ", Blink: "xx", Rating: RatingSafe},
+	"greedy":   {Eyes: "$$", Tongue: "  ", Rating: RatingSafe},
+	"paranoid": {Eyes: "@@", Tongue: "  ", Rating: RatingSafe},
+	"stoned":   {Eyes: "**", Tongue: "U ", Rating: RatingNSFW},
+	"tired":    {Eyes: "--", Tongue: "  ", Blink: "__", Rating: RatingSafe},
+	"wired":    {Eyes: "OO", Tongue: "  ", Rating: RatingSafe},
+	"young":    {Eyes: "..", Tongue: "  ", Rating: RatingSafe},
 }
 
 var moodNames = []string{"borg", "dead", "greedy", "paranoid", "stoned", "tired", "wired", "young"}
@@ -29,7 +30,7 @@ func GetMood(name string) (Mood, bool) {
 
 // RandomMood returns a random mood name
 func RandomMood() string {
-	return moodNames[rand.Intn(len(moodNames))]
+	return Selector{}.RandomMood()
 }
 
 // ListMoods returns a list of all available mood names
@@ -0,0 +1,74 @@
+package cow
+
+import "math/rand"
+
+// Rand is a seeded random source. It remembers its seed and whether it has
+// been drawn from, so output that used randomness can be reproduced. A nil
+// *Rand draws from the global math/rand source.
+//
+// A Rand is not safe for concurrent use; create one per request.
+type Rand struct {
+	rng  *rand.Rand
+	seed int64
+	used bool
+}
+
+// NewRand returns a random source seeded with seed
+func NewRand(seed int64) *Rand {
+	return &Rand{rng: rand.New(rand.NewSource(seed)), seed: seed}
+}
+
+// NewSeed returns a fresh seed for NewRand
+func NewSeed() int64 {
+	return rand.Int63()
+}
+
+// Seed returns the seed the source was created with
+func (r *Rand) Seed() int64 {
+	return r.seed
+}
+
+// Used reports whether any random value has been drawn from the source
+func (r *Rand) Used() bool {
+	return r != nil && r.used
+}
+
+// Intn returns a random int in [0, n)
+func (r *Rand) Intn(n int) int {
+	if r == nil {
+		return rand.Intn(n)
+	}
+	r.used = true
+	return r.rng.Intn(n)
+}
+
+// pick returns a random element of items
+func (r *Rand) pick(items []string) string {
+	return items[r.Intn(len(items))]
+}
+
+// weighted returns a random element of items, favouring those with a higher
+// weight. Items missing from weights have weight 1; items must have a
+// positive total weight.
+func (r *Rand) weighted(items []string, weights map[string]int) string {
+	total := 0
+	for _, item := range items {
+		total += weightOf(item, weights)
+	}
+
+	n := r.Intn(total)
+	for _, item := range items {
+		n -= weightOf(item, weights)
+		if n < 0 {
+			return item
+		}
+	}
+	return items[len(items)-1]
+}
+
+func weightOf(item string, weights map[string]int) int {
+	if w, ok := weights[item]; ok {
+		return w
+	}
+	return 1
+}
@@ -0,0 +1,56 @@
+package cow
+
+import "testing"
+
+func TestRand_Deterministic(t *testing.T) {
+	a := Selector{Rand: NewRand(42)}
+	b := Selector{Rand: NewRand(42)}
+
+	for i := 0; i < 20; i++ {
+		if ca, cb := a.RandomCow(), b.RandomCow(); ca != cb {
+			t.Fatalf("draw %d: RandomCow() = %s and %s with the same seed", i, ca, cb)
+		}
+		if ma, mb := a.RandomMood(), b.RandomMood(); ma != mb {
+			t.Fatalf("draw %d: RandomMood() = %s and %s with the same seed", i, ma, mb)
+		}
+		if ma, mb := a.RandomMessage(), b.RandomMessage(); ma != mb {
+			t.Fatalf("draw %d: RandomMessage() = %s and %s with the same seed", i, ma, mb)
+		}
+	}
+}
+
+func TestRand_Used(t *testing.T) {
+	rng := NewRand(7)
+	if rng.Used() {
+		t.Error("new Rand should not be used")
+	}
+	if rng.Seed() != 7 {
+		t.Errorf("Seed() = %d, want 7", rng.Seed())
+	}
+
+	(&Renderer{Rand: rng}).Render([]string{"hello"}, "default", "", ActionSay, 40)
+	if rng.Used() {
+		t.Error("rendering a fixed message should not use randomness")
+	}
+
+	(&Renderer{Rand: rng}).Render(nil, "default", "", ActionSay, 40)
+	if !rng.Used() {
+		t.Error("rendering without a message should use randomness")
+	}
+
+	var nilRand *Rand
+	if nilRand.Used() {
+		t.Error("nil Rand should never report used")
+	}
+	if n := nilRand.Intn(3); n < 0 || n >= 3 {
+		t.Errorf("nil Rand Intn(3) = %d", n)
+	}
+}
+
+func TestRenderer_Deterministic(t *testing.T) {
+	a := (&Renderer{Rand: NewRand(99)}).Render(nil, "default", "", ActionSay, 40)
+	b := (&Renderer{Rand: NewRand(99)}).Render(nil, "default", "", ActionSay, 40)
+	if a != b {
+		t.Errorf("same seed rendered different output:\n%s\n%s", a, b)
+	}
+}
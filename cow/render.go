@@ -3,12 +3,13 @@ package cow
 import (
 	"bytes"
 	"fmt"
-	"html/template"
 	"log/slog"
 	"strings"
+	"text/template"
 
 	runewidth "github.com/mattn/go-runewidth"
-	"github.com/mitchellh/go-wordwrap"
+	"github.com/vnykmshr/gowsay/figlet"
+	"github.com/vnykmshr/gowsay/qr"
 )
 
 // Action types for cowsay
@@ -17,27 +18,90 @@ const (
 	ActionThink = "think"
 )
 
-// Face represents the cow's facial expression
+// Face represents the cow's facial expression, and the position of its
+// tail for cows with one
 type Face struct {
-	Eyes     string
-	Tongue   string
-	Thoughts string
-	cowfile  string
+	Eyes        string
+	Tongue      string
+	Thoughts    string
+	Tail        string
+	blink       string
+	cowfile     string
+	accessories []string
+}
+
+// Renderer generates cowsay output, drawing any randomness it needs from
+// Rand. Prose is run through Filters, in order, before wrapping.
+// Prose is styled by inline markdown (*bold*, _italic_, ~strike~ and
+// bullet lists); fenced code blocks (```go) are kept verbatim, and
+// highlighted in the ANSI format. With a Font, each text element
+// is drawn as a FIGlet banner instead of being word wrapped. With a QR
+// code, the balloon holds the code instead of the text. In the ANSI format
+// the cow is drawn in Color, one of Colors, if it is set. The cow wears
+// Accessories it has anchors for, in order.
+type Renderer struct {
+	Rand        *Rand
+	Filters     []Filter
+	Font        *figlet.Font
+	QR          *qr.Code
+	Format      Format
+	Color       string
+	Accessories []string
 }
 
 // Render generates cowsay output with the specified parameters
 func Render(text []string, cowName, mood, action string, columns int) string {
-	inputs := wrapText(text, columns)
-	width := maxWidth(inputs)
-	msgs := padLines(inputs, width)
+	return (&Renderer{}).Render(text, cowName, mood, action, columns)
+}
+
+// Render generates cowsay output with the specified parameters
+func (r *Renderer) Render(text []string, cowName, mood, action string, columns int) string {
+	lines := r.layout(text, columns)
+	return r.draw(lines, linesWidth(lines), newFace(cowName, mood), action)
+}
+
+// layout filters the message text and lays it out in lines of spans.
+// Filters apply to banner text and to prose, not to code blocks.
+func (r *Renderer) layout(text []string, columns int) [][]span {
+	var lines [][]span
+	switch {
+	case r.QR != nil:
+		lines = plainLines(r.QR.Lines())
+	case r.Font != nil:
+		filtered := make([]string, len(text))
+		for i, t := range text {
+			filtered[i] = ApplyFilters(t, r.Filters)
+		}
+		lines = plainLines(bannerText(filtered, r.Font))
+	default:
+		lines = messageLines(text, columns, r.Format, r.Filters)
+	}
+	return lines
+}
+
+// draw renders lines of the message, padded to width, in a balloon above
+// the cow with the given face
+func (r *Renderer) draw(lines [][]span, width int, face *Face, action string) string {
+	var msgs []string
+	for _, line := range lines {
+		var sb strings.Builder
+		for _, s := range line {
+			sb.WriteString(r.Format.span(s))
+		}
+		sb.WriteString(strings.Repeat(" ", width-runewidth.StringWidth(spanText(line))))
+		msgs = append(msgs, sb.String())
+	}
 
 	if len(msgs) == 0 {
-		msgs = append(msgs, RandomMessage())
+		msgs = append(msgs, r.Rand.pick(moos))
 	}
 
-	face := newFace(cowName, mood)
 	balloon := buildBalloon(face, action, msgs, width)
+	face.accessories = r.Accessories
 	cow := renderCow(face)
+	if sgr, ok := cowColors[r.Color]; ok && r.Format == FormatANSI {
+		cow = colorLines(cow, sgr)
+	}
 
 	return fmt.Sprintf("%s%s", balloon, cow)
 }
@@ -47,53 +111,61 @@ func newFace(cowName, moodName string) *Face {
 	face := &Face{
 		Eyes:    "oo",
 		Tongue:  "  ",
+		blink:   "--",
 		cowfile: cowName,
 	}
+	if t, ok := tails[cowName]; ok {
+		face.Tail = t[0]
+	}
 
 	if moodName != "" {
 		if mood, ok := GetMood(moodName); ok {
 			face.Eyes = mood.Eyes
 			face.Tongue = mood.Tongue
+			if mood.Blink != "" {
+				face.blink = mood.Blink
+			}
 		}
 	}
 
 	return face
 }
 
-// renderCow renders the cow template with the given face
+// renderCow renders the cow template with the given face, wearing its
+// accessories
 func renderCow(f *Face) string {
+	art, err := execute(f)
+	if err != nil {
+		slog.Error("failed to render cow template", "cow", f.cowfile, "error", err)
+		return err.Error()
+	}
+	if len(f.accessories) > 0 {
+		art = wear(art, f)
+	}
+	return art
+}
+
+// execute fills in the cow template with the face
+func execute(f *Face) (string, error) {
 	tmpl := template.Must(template.New("cow").Parse(cows[f.cowfile]))
 
 	var buf bytes.Buffer
 	if err := tmpl.Execute(&buf, f); err != nil {
-		slog.Error("failed to render cow template", "cow", f.cowfile, "error", err)
-		return err.Error()
+		return "", err
 	}
-
-	return buf.String()
+	return buf.String(), nil
 }
 
-// wrapText processes input text with word wrapping and tab expansion
-func wrapText(args []string, columns int) []string {
+// bannerText draws each text element in the font, one row of letters per
+// element. Banners are not wrapped.
+func bannerText(args []string, font *figlet.Font) []string {
 	var msgs []string
 	for _, arg := range args {
-		expanded := strings.Replace(arg, "\t", "        ", -1)
-		wrapped := wordwrap.WrapString(expanded, uint(columns))
-		msgs = append(msgs, strings.Split(wrapped, "\n")...)
+		msgs = append(msgs, font.Render(arg)...)
 	}
 	return msgs
 }
 
-// padLines pads each line to the specified width
-func padLines(msgs []string, width int) []string {
-	var ret []string
-	for _, m := range msgs {
-		s := m + strings.Repeat(" ", width-runewidth.StringWidth(m))
-		ret = append(ret, s)
-	}
-	return ret
-}
-
 // buildBalloon constructs the speech/thought balloon
 func buildBalloon(f *Face, action string, msgs []string, width int) string {
 	lineCount := len(msgs)
@@ -141,3 +213,12 @@ func maxWidth(msgs []string) int {
 	}
 	return max
 }
+
+// linesWidth returns the display width of the widest line of spans
+func linesWidth(lines [][]span) int {
+	texts := make([]string, len(lines))
+	for i, line := range lines {
+		texts[i] = spanText(line)
+	}
+	return maxWidth(texts)
+}
@@ -1,8 +1,12 @@
 package cow
 
 import (
+	"slices"
 	"strings"
 	"testing"
+
+	"github.com/vnykmshr/gowsay/figlet"
+	"github.com/vnykmshr/gowsay/qr"
 )
 
 func TestRender(t *testing.T) {
@@ -363,3 +367,49 @@ func TestRender_SpecialCharacters(t *testing.T) {
 		})
 	}
 }
+
+func TestRenderer_Font(t *testing.T) {
+	font, _ := figlet.Builtin("small")
+	r := &Renderer{Font: font, Filters: []Filter{FilterFunc(strings.ToUpper)}}
+	got := r.Render([]string{"hi there"}, "default", "", ActionSay, 4)
+
+	want := []string{
+		" _________________________________",
+		"/ # # ###     ### # # ### ##  ### \\",
+		"| # #  #       #  # # #   # # #   |",
+		"| ###  #       #  ### ##  ##  ##  |",
+		"| # #  #       #  # # #   # # #   |",
+		"\\ # # ###      #  # # ### # # ### /",
+		" ---------------------------------",
+	}
+	if lines := strings.Split(got, "\n"); !slices.Equal(lines[:len(want)], want) {
+		t.Errorf("Render() with a font =\n%s\nwant\n%s", got, strings.Join(want, "\n"))
+	}
+}
+
+func TestRenderer_QR(t *testing.T) {
+	code, err := qr.Encode("https://example.com", qr.Options{QuietZone: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := (&Renderer{QR: code}).Render([]string{"ignored"}, "default", "", ActionSay, 10)
+
+	lines := strings.Split(got, "\n")
+	for i, want := range code.Lines() {
+		if line := lines[i+1]; line[len("| "):len(line)-len(" |")] != want {
+			t.Errorf("balloon line %d = %q, want %q", i+1, line, want)
+		}
+	}
+	if strings.Contains(got, "ignored") {
+		t.Error("the QR code should replace the text")
+	}
+}
+
+func TestRender_NoEscaping(t *testing.T) {
+	// Cows are plain text: characters like < must not be HTML-escaped
+	for _, name := range List() {
+		if got := Render([]string{"moo"}, name, "", ActionSay, 40); strings.Contains(got, "&lt;") || strings.Contains(got, "&amp;") {
+			t.Errorf("%s: output is HTML-escaped:\n%s", name, got)
+		}
+	}
+}
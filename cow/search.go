@@ -0,0 +1,133 @@
+package cow
+
+import (
+	"sort"
+	"strings"
+)
+
+// Match is a cow search result ranked by its fuzzy score
+type Match struct {
+	Info
+	Score float64 `json:"score"`
+}
+
+// minSimilarity is the lowest edit-distance similarity that still counts as a typo
+const minSimilarity = 0.6
+
+// Search returns the cows matching query, best match first. The query is
+// compared against names, tags and descriptions, tolerating typos. When tag
+// is set, only cows carrying that tag are returned; an empty query then lists
+// every cow with the tag.
+func (s Selector) Search(query, tag string) []Match {
+	query = strings.ToLower(strings.TrimSpace(query))
+
+	var matches []Match
+	for _, info := range s.ListInfo() {
+		if tag != "" && !info.HasTag(tag) {
+			continue
+		}
+
+		score := 1.0
+		if query != "" {
+			score = matchScore(query, info)
+		}
+		if score > 0 {
+			matches = append(matches, Match{Info: info, Score: score})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].Score != matches[j].Score {
+			return matches[i].Score > matches[j].Score
+		}
+		return matches[i].Name < matches[j].Name
+	})
+	return matches
+}
+
+// Suggest returns the allowed cow whose name is closest to name, if any is
+// close enough to be a likely typo
+func (s Selector) Suggest(name string) (string, bool) {
+	return closest(name, s.List())
+}
+
+// SuggestMood returns the allowed mood whose name is closest to name, if any
+// is close enough to be a likely typo
+func (s Selector) SuggestMood(name string) (string, bool) {
+	return closest(name, s.ListMoods())
+}
+
+// matchScore rates how well query matches the cow, from 0 (no match) to 1
+func matchScore(query string, info Info) float64 {
+	name := info.Name
+	switch {
+	case name == query:
+		return 1
+	case strings.HasPrefix(name, query):
+		return 0.9
+	case strings.Contains(name, query):
+		return 0.8
+	}
+
+	var best float64
+	if info.HasTag(query) {
+		best = 0.7
+	}
+	if strings.Contains(strings.ToLower(info.Description), query) {
+		best = max(best, 0.6)
+	}
+	if sim := similarity(query, name); sim >= minSimilarity {
+		best = max(best, 0.7*sim)
+	}
+	return best
+}
+
+// closest returns the candidate most similar to name, provided it is
+// similar enough to be a likely typo
+func closest(name string, candidates []string) (string, bool) {
+	name = strings.ToLower(name)
+
+	var best string
+	var bestSim float64
+	for _, c := range candidates {
+		if sim := similarity(name, c); sim > bestSim {
+			best, bestSim = c, sim
+		}
+	}
+	if bestSim < minSimilarity {
+		return "", false
+	}
+	return best, true
+}
+
+// similarity turns the edit distance between a and b into a score from 0 to 1
+func similarity(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	longest := max(len(ra), len(rb))
+	if longest == 0 {
+		return 1
+	}
+	return 1 - float64(levenshtein(ra, rb))/float64(longest)
+}
+
+// levenshtein returns the number of single-rune edits needed to turn a into b
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,104 @@
+package cow
+
+import "testing"
+
+func TestSelector_Search(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		tag       string
+		wantFirst string
+	}{
+		{"exact name", "dragon", "", "dragon"},
+		{"prefix", "stego", "", "stegosaurus"},
+		{"typo", "stegasaurus", "", "stegosaurus"},
+		{"description", "penguin", "", "tux"},
+		{"tag only", "", TagHoliday, "skeleton"},
+		{"query and tag", "dragon", TagAnimal, "dragon-and-cow"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matches := Selector{}.Search(tt.query, tt.tag)
+			if len(matches) == 0 {
+				t.Fatalf("Search(%q, %q) returned no matches", tt.query, tt.tag)
+			}
+			if matches[0].Name != tt.wantFirst {
+				t.Errorf("Search(%q, %q) first = %s, want %s", tt.query, tt.tag, matches[0].Name, tt.wantFirst)
+			}
+			for i := 1; i < len(matches); i++ {
+				if matches[i].Score > matches[i-1].Score {
+					t.Errorf("matches not ranked: %v", matches)
+				}
+			}
+			if tt.tag != "" {
+				for _, m := range matches {
+					if !m.HasTag(tt.tag) {
+						t.Errorf("%s does not carry tag %s", m.Name, tt.tag)
+					}
+				}
+			}
+		})
+	}
+
+	if matches := (Selector{}).Search("xyzzy", ""); len(matches) != 0 {
+		t.Errorf("Search(xyzzy) = %v, want no matches", matches)
+	}
+}
+
+func TestSelector_SearchSafeMode(t *testing.T) {
+	for _, m := range (Selector{SafeMode: true}).Search("bong", "") {
+		if m.Name == "bong" {
+			t.Error("safe mode search should not return bong")
+		}
+	}
+}
+
+func TestSelector_Suggest(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		want   string
+		wantOK bool
+	}{
+		{"one letter off", "stegasaurus", "stegosaurus", true},
+		{"missing letter", "dragn", "dragon", true},
+		{"case insensitive", "TUX", "tux", true},
+		{"nothing close", "xyzzy", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := Selector{}.Suggest(tt.input)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("Suggest(%s) = %s, %v, want %s, %v", tt.input, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+
+	if got, ok := (Selector{}).SuggestMood("wird"); !ok || got != "wired" {
+		t.Errorf("SuggestMood(wird) = %s, %v, want wired, true", got, ok)
+	}
+	if _, ok := (Selector{SafeMode: true}).SuggestMood("stone"); ok {
+		t.Error("safe mode should not suggest mood 'stoned'")
+	}
+}
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"abc", "", 3},
+		{"kitten", "sitting", 3},
+		{"moo", "moo", 0},
+		{"日本", "日本語", 1},
+	}
+
+	for _, tt := range tests {
+		if got := levenshtein([]rune(tt.a), []rune(tt.b)); got != tt.want {
+			t.Errorf("levenshtein(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,233 @@
+package cow
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Selector lists and picks cows and moods. In safe mode, cows and moods
+// that are not rated safe are hidden from listings, never picked at random
+// and rejected when asked for by name.
+//
+// Random picks are drawn from Rand. Cows and moods named in Weights are
+// picked that many times more often than the rest (weight 1), and those in
+// Exclude are never picked. Random cows have the anchors for all of
+// Accessories. When History and Key are set, cows picked recently for Key
+// are avoided. Random messages come from Messages, or the built-in moo
+// messages if it is empty.
+type Selector struct {
+	SafeMode    bool
+	Rand        *Rand
+	Weights     map[string]int
+	Exclude     []string
+	Accessories []string
+	History     *History
+	Key         string
+	Messages    []string
+}
+
+// CowAllowed reports whether the cow exists and may be used
+func (s Selector) CowAllowed(name string) bool {
+	if !Exists(name) {
+		return false
+	}
+	return !s.SafeMode || IsSafe(name)
+}
+
+// MoodAllowed reports whether the mood exists and may be used
+func (s Selector) MoodAllowed(name string) bool {
+	if !MoodExists(name) {
+		return false
+	}
+	return !s.SafeMode || IsSafeMood(name)
+}
+
+// List returns the names of all cows that may be used
+func (s Selector) List() []string {
+	var result []string
+	for _, name := range cowNames {
+		if s.CowAllowed(name) {
+			result = append(result, name)
+		}
+	}
+	return result
+}
+
+// ListInfo returns the metadata of all cows that may be used
+func (s Selector) ListInfo() []Info {
+	var result []Info
+	for _, info := range ListInfo() {
+		if s.CowAllowed(info.Name) {
+			result = append(result, info)
+		}
+	}
+	return result
+}
+
+// ListMoods returns the names of all moods that may be used
+func (s Selector) ListMoods() []string {
+	var result []string
+	for _, name := range moodNames {
+		if s.MoodAllowed(name) {
+			result = append(result, name)
+		}
+	}
+	return result
+}
+
+// RandomCow returns a random cow name that may be used
+func (s Selector) RandomCow() string {
+	name, ok := s.RandomTagged("")
+	if !ok {
+		return "default"
+	}
+	return name
+}
+
+// RandomTagged returns a random cow carrying tag, or any cow if tag is empty.
+// It returns false if no cow can be picked.
+func (s Selector) RandomTagged(tag string) (string, bool) {
+	var names []string
+	for _, name := range s.candidates(s.List()) {
+		if (tag == "" || hasTag(name, tag)) && s.canWear(name) {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return "", false
+	}
+
+	// Without a key there is no one to avoid repeating cows for
+	remember := s.History != nil && s.Key != ""
+	if remember {
+		if fresh := without(names, s.History.Recent(s.Key)); len(fresh) > 0 {
+			names = fresh
+		}
+	}
+
+	name := s.Rand.weighted(names, s.Weights)
+	if remember {
+		s.History.Add(s.Key, name)
+	}
+	return name, true
+}
+
+// RandomMood returns a random mood name that may be used
+func (s Selector) RandomMood() string {
+	names := s.candidates(s.ListMoods())
+	if len(names) == 0 {
+		return ""
+	}
+	return s.Rand.weighted(names, s.Weights)
+}
+
+// canWear reports whether the cow has the anchors for the accessories
+func (s Selector) canWear(name string) bool {
+	return len(s.Accessories) == 0 || CheckAccessories(name, s.Accessories) == nil
+}
+
+// candidates drops excluded and zero-weighted names
+func (s Selector) candidates(names []string) []string {
+	var result []string
+	for _, name := range without(names, s.Exclude) {
+		if w, ok := s.Weights[name]; !ok || w > 0 {
+			result = append(result, name)
+		}
+	}
+	return result
+}
+
+// RandomMessage returns a random message
+func (s Selector) RandomMessage() string {
+	if len(s.Messages) > 0 {
+		return s.Rand.pick(s.Messages)
+	}
+	return s.Rand.pick(moos)
+}
+
+// ParseRandom recognizes the "random" keyword and its tag-restricted form
+// "random:<tag>", returning the tag (empty for plain "random")
+func ParseRandom(s string) (tag string, ok bool) {
+	if s == "random" {
+		return "", true
+	}
+	tag, ok = strings.CutPrefix(s, "random:")
+	if !ok || tag == "" {
+		return "", false
+	}
+	return tag, true
+}
+
+// MaxWeight is the largest weight ParseWeights accepts, so the weights of
+// all cows and moods add up without overflowing
+const MaxWeight = 1000
+
+// ParseWeights parses a comma-separated list of name=weight pairs, such as
+// "tux=5,dragon=3,cheese=0"
+func ParseWeights(s string) (map[string]int, error) {
+	weights := make(map[string]int)
+	for _, pair := range strings.Split(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("weight %q: want name=weight", pair)
+		}
+		weight, err := strconv.Atoi(value)
+		if err != nil || weight < 0 {
+			return nil, fmt.Errorf("weight %q: want a non-negative integer", pair)
+		}
+		if weight > MaxWeight {
+			return nil, fmt.Errorf("weight %q: want at most %d", pair, MaxWeight)
+		}
+		weights[strings.TrimSpace(name)] = weight
+	}
+	return weights, nil
+}
+
+// ParseList parses a comma-separated list of names
+func ParseList(s string) []string {
+	var names []string
+	for _, name := range strings.Split(s, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// hasTag reports whether the cow carries the given tag
+func hasTag(name, tag string) bool {
+	info, ok := cowInfo[name]
+	return ok && info.HasTag(tag)
+}
+
+// without returns names minus any that appear in drop
+func without(names, drop []string) []string {
+	if len(drop) == 0 {
+		return names
+	}
+	var result []string
+	for _, name := range names {
+		if !slices.Contains(drop, name) {
+			result = append(result, name)
+		}
+	}
+	return result
+}
+
+// IsSafe reports whether the cow is rated safe for every workspace
+func IsSafe(name string) bool {
+	info, ok := cowInfo[name]
+	return ok && info.Rating == RatingSafe
+}
+
+// IsSafeMood reports whether the mood is rated safe for every workspace
+func IsSafeMood(name string) bool {
+	mood, ok := moods[name]
+	return ok && mood.Rating == RatingSafe
+}
@@ -0,0 +1,231 @@
+package cow
+
+import "testing"
+
+func TestSelector_SafeMode(t *testing.T) {
+	safe := Selector{SafeMode: true}
+	all := Selector{}
+
+	tests := []struct {
+		name     string
+		cow      string
+		wantSafe bool
+		wantAll  bool
+	}{
+		{"safe cow", "default", true, true},
+		{"nsfw cow", "bong", false, true},
+		{"nsfw cow mutilated", "mutilated", false, true},
+		{"nonexistent", "nonexistent", false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := safe.CowAllowed(tt.cow); got != tt.wantSafe {
+				t.Errorf("safe CowAllowed(%s) = %v, want %v", tt.cow, got, tt.wantSafe)
+			}
+			if got := all.CowAllowed(tt.cow); got != tt.wantAll {
+				t.Errorf("CowAllowed(%s) = %v, want %v", tt.cow, got, tt.wantAll)
+			}
+		})
+	}
+
+	if safe.MoodAllowed("stoned") {
+		t.Error("safe mode should not allow mood 'stoned'")
+	}
+	if !all.MoodAllowed("stoned") {
+		t.Error("mood 'stoned' should be allowed outside safe mode")
+	}
+}
+
+func TestSelector_List(t *testing.T) {
+	safe := Selector{SafeMode: true}
+
+	for _, name := range safe.List() {
+		if !IsSafe(name) {
+			t.Errorf("safe List() contains unsafe cow %s", name)
+		}
+	}
+	for _, info := range safe.ListInfo() {
+		if info.Rating != RatingSafe {
+			t.Errorf("safe ListInfo() contains %s rated %s", info.Name, info.Rating)
+		}
+	}
+	for _, name := range safe.ListMoods() {
+		if !IsSafeMood(name) {
+			t.Errorf("safe ListMoods() contains unsafe mood %s", name)
+		}
+	}
+
+	if len(safe.List()) >= len(Selector{}.List()) {
+		t.Error("safe List() should hide some cows")
+	}
+}
+
+func TestSelector_Random(t *testing.T) {
+	safe := Selector{SafeMode: true}
+
+	for i := 0; i < 200; i++ {
+		if c := safe.RandomCow(); !safe.CowAllowed(c) {
+			t.Fatalf("safe RandomCow() returned %s", c)
+		}
+		if m := safe.RandomMood(); !safe.MoodAllowed(m) {
+			t.Fatalf("safe RandomMood() returned %s", m)
+		}
+	}
+}
+
+func TestSelector_Weights(t *testing.T) {
+	sel := Selector{Rand: NewRand(1), Weights: map[string]int{"tux": 1000, "borg": 1000}}
+
+	tux, borg := 0, 0
+	for i := 0; i < 100; i++ {
+		if sel.RandomCow() == "tux" {
+			tux++
+		}
+		if sel.RandomMood() == "borg" {
+			borg++
+		}
+	}
+	if tux < 80 || borg < 80 {
+		t.Errorf("heavily weighted picks: tux %d/100, borg %d/100, want most", tux, borg)
+	}
+
+	zero := Selector{Weights: map[string]int{"default": 0}}
+	for i := 0; i < 200; i++ {
+		if c := zero.RandomCow(); c == "default" {
+			t.Fatal("cow with weight 0 was picked")
+		}
+	}
+}
+
+func TestSelector_Exclude(t *testing.T) {
+	sel := Selector{Exclude: []string{"default", "tux", "borg"}}
+
+	for i := 0; i < 200; i++ {
+		if c := sel.RandomCow(); c == "default" || c == "tux" {
+			t.Fatalf("excluded cow %s was picked", c)
+		}
+		if m := sel.RandomMood(); m == "borg" {
+			t.Fatal("excluded mood borg was picked")
+		}
+	}
+}
+
+func TestSelector_RandomTagged(t *testing.T) {
+	sel := Selector{}
+	for i := 0; i < 50; i++ {
+		name, ok := sel.RandomTagged(TagHoliday)
+		if !ok {
+			t.Fatal("RandomTagged(holiday) found nothing")
+		}
+		if !hasTag(name, TagHoliday) {
+			t.Fatalf("RandomTagged(holiday) = %s, which is not tagged holiday", name)
+		}
+	}
+
+	if _, ok := sel.RandomTagged("no-such-tag"); ok {
+		t.Error("RandomTagged(no-such-tag) should find nothing")
+	}
+}
+
+func TestSelector_NoRepeat(t *testing.T) {
+	history := NewHistory(1)
+	sel := Selector{Rand: NewRand(3), History: history, Key: "C123"}
+
+	prev := ""
+	for i := 0; i < 100; i++ {
+		name, _ := sel.RandomTagged(TagHoliday)
+		if name == prev {
+			t.Fatalf("pick %d repeated %s", i, name)
+		}
+		prev = name
+	}
+
+	// A different key has its own history
+	other := sel
+	other.Key = "C456"
+	if got := history.Recent("C456"); len(got) != 0 {
+		t.Errorf("Recent(C456) = %v, want empty", got)
+	}
+	other.RandomCow()
+	if got := history.Recent("C456"); len(got) != 1 {
+		t.Errorf("Recent(C456) = %v, want one pick", got)
+	}
+
+	// Without a key nothing is avoided or recorded
+	anon := sel
+	anon.Key = ""
+	anon.RandomCow()
+	if got := history.Recent(""); len(got) != 0 {
+		t.Errorf("Recent(\"\") = %v, want empty", got)
+	}
+}
+
+func TestHistory_Window(t *testing.T) {
+	h := NewHistory(2)
+	for _, name := range []string{"a", "b", "c"} {
+		h.Add("k", name)
+	}
+
+	got := h.Recent("k")
+	if len(got) != 2 || got[0] != "b" || got[1] != "c" {
+		t.Errorf("Recent() = %v, want [b c]", got)
+	}
+}
+
+func TestParseRandom(t *testing.T) {
+	tests := []struct {
+		input   string
+		wantTag string
+		wantOK  bool
+	}{
+		{"random", "", true},
+		{"random:animal", "animal", true},
+		{"random:", "", false},
+		{"default", "", false},
+	}
+
+	for _, tt := range tests {
+		tag, ok := ParseRandom(tt.input)
+		if tag != tt.wantTag || ok != tt.wantOK {
+			t.Errorf("ParseRandom(%q) = %q, %v, want %q, %v", tt.input, tag, ok, tt.wantTag, tt.wantOK)
+		}
+	}
+}
+
+func TestParseWeights(t *testing.T) {
+	weights, err := ParseWeights("tux=5, dragon=3,cheese=0")
+	if err != nil {
+		t.Fatalf("ParseWeights() error = %v", err)
+	}
+	if weights["tux"] != 5 || weights["dragon"] != 3 || weights["cheese"] != 0 {
+		t.Errorf("ParseWeights() = %v", weights)
+	}
+
+	if weights, err := ParseWeights(""); err != nil || len(weights) != 0 {
+		t.Errorf("ParseWeights(\"\") = %v, %v, want empty", weights, err)
+	}
+
+	if weights, err := ParseWeights("tux=1000"); err != nil || weights["tux"] != MaxWeight {
+		t.Errorf("ParseWeights(\"tux=1000\") = %v, %v, want the maximum weight", weights, err)
+	}
+
+	for _, bad := range []string{"tux", "tux=x", "tux=-1", "tux=1001", "tux=9223372036854775807,dragon=5"} {
+		if _, err := ParseWeights(bad); err == nil {
+			t.Errorf("ParseWeights(%q) should fail", bad)
+		}
+	}
+}
+
+func TestSelector_RandomMessage(t *testing.T) {
+	if msg := (Selector{}).RandomMessage(); msg == "" {
+		t.Error("RandomMessage() returned empty string")
+	}
+
+	sel := Selector{Messages: []string{"only\nfortune"}}
+	for i := 0; i < 10; i++ {
+		if msg := sel.RandomMessage(); msg != "only\nfortune" {
+			t.Fatalf("RandomMessage() = %q, want the configured message", msg)
+		}
+	}
+}
@@ -0,0 +1,287 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"image"
+	_ "image/jpeg" // JPEG images for cowfile from-image
+	_ "image/png"  // PNG images for cowfile from-image
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/vnykmshr/gowsay/cow"
+	"github.com/vnykmshr/gowsay/cowfile"
+	"github.com/vnykmshr/gowsay/locale"
+)
+
+// runCowfile implements the "cowfile" subcommand, which makes cow
+// templates, with a subcommand of its own
+func runCowfile(args []string, w io.Writer) error {
+	usage := "Usage:\n  gowsay cowfile from-image [options] <image>\n  gowsay cowfile lint [options] <file>...\n  gowsay cowfile export [options] <name>... | --all\n  gowsay cowfile import [options] <file>\n"
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, usage)
+		return flag.ErrHelp
+	}
+	switch args[0] {
+	case "from-image":
+		return runFromImage(args[1:], w)
+	case "lint":
+		return runLint(args[1:], w)
+	case "export":
+		return runExport(args[1:], w)
+	case "import":
+		return runImport(args[1:], w)
+	case "-h", "-help", "--help":
+		fmt.Fprint(os.Stderr, usage)
+		return flag.ErrHelp
+	}
+	p := locale.NewPrinter(locale.FromEnv())
+	return errors.New(p.Sprintf("cowfile command '%s' not found", args[0]))
+}
+
+// runFromImage converts a PNG or JPEG image into a cow template
+func runFromImage(args []string, w io.Writer) error {
+	fs := flag.NewFlagSet("cowfile from-image", flag.ContinueOnError)
+	var (
+		width    = fs.Int("w", 40, "Width of the art in columns")
+		style    = fs.String("style", cowfile.StyleASCII, "Art style: ascii shading or Unicode half blocks")
+		dither   = fs.Bool("dither", false, "Dither shades, for photos and gradients")
+		invert   = fs.Bool("invert", false, "Draw light pixels, for light art on a dark background")
+		outFile  = fs.String("o", "", "Write the cowfile to a file instead of stdout")
+		thoughts []image.Point
+		eyes     []image.Point
+	)
+	p := locale.NewPrinter(locale.FromEnv())
+	fs.Func("thoughts", "Thoughts connector position as column,row in the art (repeatable; default: two lines above the art)", func(s string) error {
+		pt, err := parsePoint(p, s)
+		thoughts = append(thoughts, pt)
+		return err
+	})
+	fs.Func("eyes", "Eyes position as column,row in the art (repeatable)", func(s string) error {
+		pt, err := parsePoint(p, s)
+		eyes = append(eyes, pt)
+		return err
+	})
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage:\n  gowsay cowfile from-image [options] <image>\n\nOptions:\n")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return flag.ErrHelp
+	}
+
+	if *width < 1 || *width > cowfile.MaxWidth {
+		return errors.New(p.Sprintf("width must be between 1 and %d", cowfile.MaxWidth))
+	}
+	if *style != cowfile.StyleASCII && *style != cowfile.StyleBlocks {
+		return errors.New(p.Sprintf("style '%s' must be ascii or blocks", *style))
+	}
+
+	f, err := os.Open(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return errors.New(p.Sprintf("%s is not a PNG or JPEG image", fs.Arg(0)))
+	}
+
+	art, err := cowfile.FromImage(img, cowfile.Options{
+		Width:    *width,
+		Style:    *style,
+		Dither:   *dither,
+		Invert:   *invert,
+		Thoughts: thoughts,
+		Eyes:     eyes,
+	})
+	if err != nil {
+		return err
+	}
+	if *outFile != "" {
+		return os.WriteFile(*outFile, []byte(art), 0o644)
+	}
+	_, err = io.WriteString(w, art)
+	return err
+}
+
+// runLint checks cowfiles, or standard input for "-", and writes a JSON
+// array of reports. It fails if any cowfile has errors, so CI jobs can
+// run it as a check.
+func runLint(args []string, w io.Writer) error {
+	fs := flag.NewFlagSet("cowfile lint", flag.ContinueOnError)
+	width := fs.Int("width", cowfile.LintWidth, "Warn about lines wider than this many columns")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage:\n  gowsay cowfile lint [options] <file>...\n\nOptions:\n")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return flag.ErrHelp
+	}
+
+	reports := make([]cowfile.Report, 0, fs.NArg())
+	errs := 0
+	for _, file := range fs.Args() {
+		var src []byte
+		var err error
+		if file == "-" {
+			src, err = io.ReadAll(os.Stdin)
+		} else {
+			src, err = os.ReadFile(file)
+		}
+		if err != nil {
+			return err
+		}
+		r := cowfile.NewReport(file, string(src), *width)
+		for _, issue := range r.Issues {
+			if issue.Severity == cowfile.SeverityError {
+				errs++
+			}
+		}
+		reports = append(reports, r)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(reports); err != nil {
+		return err
+	}
+	if errs > 0 {
+		p := locale.NewPrinter(locale.FromEnv())
+		return errors.New(p.Sprintf("cowfile lint found %d errors", errs))
+	}
+	return nil
+}
+
+// runExport converts built-in cows into classic Perl cowfiles. A single
+// cow is written to stdout unless -dir is set; several, or -all, are
+// written as <name>.cow files in -dir or the current directory.
+func runExport(args []string, w io.Writer) error {
+	fs := flag.NewFlagSet("cowfile export", flag.ContinueOnError)
+	all := fs.Bool("all", false, "Export every cow")
+	dir := fs.String("dir", "", "Directory to write <name>.cow files to")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage:\n  gowsay cowfile export [options] <name>... | --all\n\nOptions:\n")
+		fs.PrintDefaults()
+	}
+	// Flags may follow the cow names, as in "export default --dir out/"
+	var names []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return err
+		}
+		if fs.NArg() == 0 {
+			break
+		}
+		names = append(names, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+	if *all == (len(names) > 0) {
+		fs.Usage()
+		return flag.ErrHelp
+	}
+
+	p := locale.NewPrinter(locale.FromEnv())
+	if *all {
+		for _, name := range cow.List() {
+			if cow.Exists(name) {
+				names = append(names, name)
+			}
+		}
+	}
+	for _, name := range names {
+		if !cow.Exists(name) {
+			return errors.New(p.Sprintf("cow '%s' not found", name))
+		}
+	}
+	if *dir == "" && len(names) > 1 {
+		*dir = "."
+	}
+	if *dir != "" {
+		if err := os.MkdirAll(*dir, 0o755); err != nil {
+			return err
+		}
+	}
+
+	for _, name := range names {
+		src, _ := cow.Template(name)
+		perl, err := cowfile.ToPerl(src, cow.Tail(name))
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		info, _ := cow.GetInfo(name)
+		perl = fmt.Sprintf("##\n## %s\n## Exported from gowsay\n##\n%s", info.Description, perl)
+		if *dir == "" {
+			if _, err := io.WriteString(w, perl); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := os.WriteFile(filepath.Join(*dir, name+".cow"), []byte(perl), 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runImport converts a classic Perl cowfile, or standard input for "-",
+// into a cow template: the reverse of export
+func runImport(args []string, w io.Writer) error {
+	fs := flag.NewFlagSet("cowfile import", flag.ContinueOnError)
+	outFile := fs.String("o", "", "Write the template to a file instead of stdout")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage:\n  gowsay cowfile import [options] <file>\n\nOptions:\n")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return flag.ErrHelp
+	}
+
+	var src []byte
+	var err error
+	if file := fs.Arg(0); file == "-" {
+		src, err = io.ReadAll(os.Stdin)
+	} else {
+		src, err = os.ReadFile(file)
+	}
+	if err != nil {
+		return err
+	}
+	tmpl, err := cowfile.FromPerl(string(src))
+	if err != nil {
+		return fmt.Errorf("%s: %w", fs.Arg(0), err)
+	}
+	if *outFile != "" {
+		return os.WriteFile(*outFile, []byte(tmpl), 0o644)
+	}
+	_, err = io.WriteString(w, tmpl)
+	return err
+}
+
+// parsePoint reads a column,row position
+func parsePoint(p *locale.Printer, s string) (image.Point, error) {
+	col, row, ok := strings.Cut(s, ",")
+	x, errX := strconv.Atoi(strings.TrimSpace(col))
+	y, errY := strconv.Atoi(strings.TrimSpace(row))
+	if !ok || errX != nil || errY != nil || x < 0 || y < 0 {
+		return image.Point{}, errors.New(p.Sprintf("position '%s' must be column,row", s))
+	}
+	return image.Pt(x, y), nil
+}
@@ -0,0 +1,204 @@
+package cowfile
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"strings"
+)
+
+// Art styles for FromImage: ASCII characters shaded by density, or
+// Unicode half blocks with two pixels to a character cell
+const (
+	StyleASCII  = "ascii"
+	StyleBlocks = "blocks"
+)
+
+// MaxWidth is the widest art FromImage draws, in columns
+const MaxWidth = 200
+
+// ramp shades ASCII art from blank to full ink
+const ramp = " .:-=+*#%@"
+
+// halfBlocks are the characters for the top and bottom pixels of a cell,
+// indexed by top | bottom<<1
+var halfBlocks = [4]string{" ", "▀", "▄", "█"}
+
+// Placeholders in cow templates
+const (
+	thoughts = "{{.Thoughts}}"
+	eyes     = "{{.Eyes}}"
+	tongue   = "{{.Tongue}}"
+)
+
+// Options configure FromImage. Positions are column and row in the art,
+// counting from its first line that is not blank. Without Thoughts
+// positions, two connector lines lead down to the top left of the art.
+type Options struct {
+	Width    int
+	Style    string
+	Dither   bool
+	Invert   bool
+	Thoughts []image.Point
+	Eyes     []image.Point
+}
+
+// FromImage draws an image as a cow template, with dark pixels as ink, or
+// light ones if Invert is set. Transparent pixels are blank. The thoughts
+// connector and two-character eyes placeholders replace the art at the
+// given positions.
+func FromImage(img image.Image, opts Options) (string, error) {
+	if opts.Width < 1 || opts.Width > MaxWidth {
+		return "", fmt.Errorf("width must be between 1 and %d", MaxWidth)
+	}
+	b := img.Bounds()
+	if b.Empty() {
+		return "", errors.New("image is empty")
+	}
+
+	// Character cells are about twice as tall as they are wide, so the art
+	// has half a row per column of the image's aspect ratio; half blocks
+	// have two square pixels to a cell
+	height := max(1, int(math.Round(float64(opts.Width)*float64(b.Dy())/float64(b.Dx())/2)))
+	var rows [][]string
+	switch opts.Style {
+	case StyleASCII, "":
+		levels := quantize(sample(img, opts.Width, height, opts.Invert), len(ramp), opts.Dither)
+		for _, line := range levels {
+			row := make([]string, len(line))
+			for x, l := range line {
+				row[x] = string(ramp[l])
+			}
+			rows = append(rows, row)
+		}
+	case StyleBlocks:
+		pixels := quantize(sample(img, opts.Width, 2*height, opts.Invert), 2, opts.Dither)
+		for y := 0; y < len(pixels); y += 2 {
+			row := make([]string, opts.Width)
+			for x := range row {
+				row[x] = halfBlocks[pixels[y][x]|pixels[y+1][x]<<1]
+			}
+			rows = append(rows, row)
+		}
+	default:
+		return "", fmt.Errorf("style '%s' must be %s or %s", opts.Style, StyleASCII, StyleBlocks)
+	}
+
+	rows = trim(rows)
+	connectors := opts.Thoughts
+	if len(connectors) == 0 {
+		rows = append([][]string{{}, {}}, rows...)
+		connectors = []image.Point{{2, 0}, {3, 1}}
+	}
+	for _, p := range connectors {
+		if err := place(rows, p, thoughts, 1); err != nil {
+			return "", err
+		}
+	}
+	for _, p := range opts.Eyes {
+		if err := place(rows, p, eyes, 2); err != nil {
+			return "", err
+		}
+	}
+	return render(rows), nil
+}
+
+// place puts a placeholder over width cells of the art at p, extending
+// the row with blanks if it is short
+func place(rows [][]string, p image.Point, placeholder string, width int) error {
+	if p.Y < 0 || p.Y >= len(rows) || p.X < 0 || p.X >= MaxWidth {
+		return fmt.Errorf("position %d,%d is outside the art, which has %d rows", p.X, p.Y, len(rows))
+	}
+	for len(rows[p.Y]) < p.X+width {
+		rows[p.Y] = append(rows[p.Y], " ")
+	}
+	rows[p.Y][p.X] = placeholder
+	for i := 1; i < width; i++ {
+		rows[p.Y][p.X+i] = ""
+	}
+	return nil
+}
+
+// trim drops blank rows from the top and bottom of the art
+func trim(rows [][]string) [][]string {
+	blank := func(row []string) bool { return strings.TrimSpace(strings.Join(row, "")) == "" }
+	for len(rows) > 0 && blank(rows[0]) {
+		rows = rows[1:]
+	}
+	for len(rows) > 0 && blank(rows[len(rows)-1]) {
+		rows = rows[:len(rows)-1]
+	}
+	return rows
+}
+
+// render joins the cells of the art into a template, without trailing
+// blanks
+func render(rows [][]string) string {
+	var sb strings.Builder
+	for _, row := range rows {
+		sb.WriteString(strings.TrimRight(strings.Join(row, ""), " "))
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+// sample scales an image to width by height, as the amount of ink in each
+// pixel from 0 to 1: its darkness, or lightness if invert is set, weighted
+// by its opacity
+func sample(img image.Image, width, height int, invert bool) [][]float64 {
+	b := img.Bounds()
+	out := make([][]float64, height)
+	for y := range out {
+		out[y] = make([]float64, width)
+		y0 := b.Min.Y + y*b.Dy()/height
+		y1 := max(y0+1, b.Min.Y+(y+1)*b.Dy()/height)
+		for x := range out[y] {
+			x0 := b.Min.X + x*b.Dx()/width
+			x1 := max(x0+1, b.Min.X+(x+1)*b.Dx()/width)
+
+			var sum float64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					c := color.NRGBA64Model.Convert(img.At(sx, sy)).(color.NRGBA64)
+					lum := (0.299*float64(c.R) + 0.587*float64(c.G) + 0.114*float64(c.B)) / 0xffff
+					if !invert {
+						lum = 1 - lum
+					}
+					sum += lum * float64(c.A) / 0xffff
+				}
+			}
+			out[y][x] = sum / float64((x1-x0)*(y1-y0))
+		}
+	}
+	return out
+}
+
+// quantize reduces ink amounts to levels steps, spreading the rounding
+// error to the pixels around each one with Floyd-Steinberg dithering if
+// dither is set
+func quantize(ink [][]float64, levels int, dither bool) [][]int {
+	out := make([][]int, len(ink))
+	for y := range ink {
+		out[y] = make([]int, len(ink[y]))
+		for x, v := range ink[y] {
+			l := int(math.Round(min(max(v, 0), 1) * float64(levels-1)))
+			out[y][x] = l
+			if !dither {
+				continue
+			}
+			diff := v - float64(l)/float64(levels-1)
+			spread := func(dx, dy int, weight float64) {
+				if ny, nx := y+dy, x+dx; ny < len(ink) && nx >= 0 && nx < len(ink[ny]) {
+					ink[ny][nx] += diff * weight
+				}
+			}
+			spread(1, 0, 7.0/16)
+			spread(-1, 1, 3.0/16)
+			spread(0, 1, 5.0/16)
+			spread(1, 1, 1.0/16)
+		}
+	}
+	return out
+}
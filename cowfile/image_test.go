@@ -0,0 +1,96 @@
+package cowfile
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"strings"
+	"testing"
+)
+
+// filled returns a w by h image of one color, with a black rectangle r
+func filled(w, h int, c color.Color, r image.Rectangle) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(img, img.Bounds(), image.NewUniform(c), image.Point{}, draw.Src)
+	draw.Draw(img, r, image.Black, image.Point{}, draw.Src)
+	return img
+}
+
+func TestFromImage(t *testing.T) {
+	// A black square in the bottom half of a white image; at 8 columns,
+	// the art has four rows of two pixels
+	img := filled(8, 8, color.White, image.Rect(2, 4, 6, 8))
+
+	tests := []struct {
+		name string
+		img  image.Image
+		opts Options
+		want string
+	}{
+		{"ascii", img, Options{Width: 8},
+			"  {{.Thoughts}}\n   {{.Thoughts}}\n  @@@@\n  @@@@\n"},
+		{"blocks", img, Options{Width: 8, Style: StyleBlocks, Thoughts: []image.Point{{0, 0}}},
+			"{{.Thoughts}} ████\n  ████\n"},
+		{"invert", img, Options{Width: 4, Invert: true, Thoughts: []image.Point{{3, 1}}},
+			"@@@@\n@  {{.Thoughts}}\n"},
+		{"eyes", img, Options{Width: 8, Thoughts: []image.Point{{0, 0}}, Eyes: []image.Point{{3, 1}}},
+			"{{.Thoughts}} @@@@\n  @{{.Eyes}}@\n"},
+		{"gray", filled(4, 2, color.Gray{0x80}, image.Rectangle{}), Options{Width: 4, Thoughts: []image.Point{{0, 0}}},
+			"{{.Thoughts}}===\n"},
+		{"transparent", filled(4, 2, color.Transparent, image.Rectangle{}), Options{Width: 4},
+			"  {{.Thoughts}}\n   {{.Thoughts}}\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FromImage(tt.img, tt.opts)
+			if err != nil {
+				t.Fatalf("FromImage() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("FromImage() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFromImage_Dither(t *testing.T) {
+	// A flat mid gray is one shade without dithering, and a mix of blank
+	// and full half blocks with it
+	img := filled(16, 16, color.Gray{0x80}, image.Rectangle{})
+	flat, err := FromImage(img, Options{Width: 16, Style: StyleBlocks})
+	if err != nil {
+		t.Fatal(err)
+	}
+	dithered, err := FromImage(img, Options{Width: 16, Style: StyleBlocks, Dither: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(flat, " ") && strings.Contains(flat, "█") {
+		t.Errorf("FromImage() without dithering mixes shades:\n%s", flat)
+	}
+	ink := strings.Count(dithered, "█")*2 + strings.Count(dithered, "▀") + strings.Count(dithered, "▄")
+	if ink < 16*16/2-16 || ink > 16*16/2+16 {
+		t.Errorf("FromImage() with dithering has %d of 256 pixels inked, want about half:\n%s", ink, dithered)
+	}
+}
+
+func TestFromImage_Errors(t *testing.T) {
+	img := filled(8, 8, color.White, image.Rect(0, 0, 8, 8))
+
+	tests := []struct {
+		name string
+		opts Options
+		want string
+	}{
+		{"width", Options{Width: 0}, "width must be between 1 and 200"},
+		{"style", Options{Width: 8, Style: "braille"}, "style 'braille' must be ascii or blocks"},
+		{"position", Options{Width: 8, Eyes: []image.Point{{1, 9}}}, "position 1,9 is outside the art"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := FromImage(img, tt.opts); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("FromImage() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,170 @@
+package cowfile
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+
+	runewidth "github.com/mattn/go-runewidth"
+	"github.com/vnykmshr/gowsay/cow"
+)
+
+// Checks that Lint runs, named in each Issue
+const (
+	CheckTemplate    = "template"
+	CheckThoughts    = "thoughts"
+	CheckEyes        = "eyes"
+	CheckTongue      = "tongue"
+	CheckWhitespace  = "trailing-whitespace"
+	CheckTabs        = "tabs"
+	CheckLineEndings = "line-endings"
+	CheckWidth       = "width"
+)
+
+// Issue severities. Errors break the cow when it is drawn; warnings are
+// worth a look but the cow still works.
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// LintWidth is the widest a cow can be, in columns, before Lint warns
+// about it by default
+const LintWidth = 80
+
+// Markers stand in for the placeholders when Lint draws a template, so it
+// can find them in the output. The replacer turns them back into
+// characters of the same width.
+const (
+	thoughtsMark = "\x00"
+	eyesMark     = "\x01\x01"
+	tongueMark   = "\x02\x02"
+)
+
+var unmark = strings.NewReplacer("\x00", `\`, "\x01", "o", "\x02", " ")
+
+// Issue is a problem Lint found in a cow template. Line and Column count
+// from 1, and are 0 for problems with the whole template.
+type Issue struct {
+	Check    string `json:"check"`
+	Severity string `json:"severity"`
+	Line     int    `json:"line,omitempty"`
+	Column   int    `json:"column,omitempty"`
+	Message  string `json:"message"`
+}
+
+// Report is the result of linting one cowfile
+type Report struct {
+	File   string  `json:"file"`
+	Valid  bool    `json:"valid"`
+	Issues []Issue `json:"issues"`
+}
+
+// NewReport lints the template src read from file. It is valid if Lint
+// finds no errors.
+func NewReport(file, src string, width int) Report {
+	r := Report{File: file, Valid: true, Issues: Lint(src, width)}
+	if r.Issues == nil {
+		r.Issues = []Issue{}
+	}
+	for _, issue := range r.Issues {
+		if issue.Severity == SeverityError {
+			r.Valid = false
+		}
+	}
+	return r
+}
+
+// Lint checks a cow template. The template must parse and draw with the
+// placeholders gowsay fills in, and have a thoughts connector that starts
+// on its first line, just below the balloon, and steps down and to the
+// right. Eyes and tongue placeholders, trailing whitespace and lines wider
+// than width columns are warnings; tabs, which each terminal draws at its
+// own width, and mixed line endings are errors.
+func Lint(src string, width int) []Issue {
+	var issues []Issue
+	add := func(check, severity string, line, col int, format string, args ...any) {
+		issues = append(issues, Issue{check, severity, line, col, fmt.Sprintf(format, args...)})
+	}
+
+	lines := strings.Split(src, "\n")
+	ended := strings.HasSuffix(src, "\n")
+	if ended {
+		lines = lines[:len(lines)-1]
+	}
+	var first string
+	mixed := false
+	for i, line := range lines {
+		// Without a final newline, the last line has no ending to compare
+		if i < len(lines)-1 || ended {
+			e := ending(strings.HasSuffix(line, "\r"))
+			if first == "" {
+				first = e
+			} else if e != first && !mixed {
+				add(CheckLineEndings, SeverityError, i+1, 0, "line ends in %s but line 1 ends in %s", e, first)
+				mixed = true
+			}
+		}
+		line = strings.TrimSuffix(line, "\r")
+		if col := strings.IndexByte(line, '\t'); col >= 0 {
+			add(CheckTabs, SeverityError, i+1, col+1, "tab character; use spaces so the art lines up in every terminal")
+		}
+		if trimmed := strings.TrimRight(line, " \t"); trimmed != line {
+			add(CheckWhitespace, SeverityWarning, i+1, len(trimmed)+1, "trailing whitespace")
+		}
+	}
+
+	tmpl, err := template.New("cow").Parse(strings.ReplaceAll(src, "\r\n", "\n"))
+	if err != nil {
+		add(CheckTemplate, SeverityError, 0, 0, "%v", err)
+		return issues
+	}
+	var out strings.Builder
+	face := &cow.Face{Eyes: eyesMark, Tongue: tongueMark, Thoughts: thoughtsMark, Tail: `\/\`}
+	if err := tmpl.Execute(&out, face); err != nil {
+		add(CheckTemplate, SeverityError, 0, 0, "%v", err)
+		return issues
+	}
+	drawn := out.String()
+
+	if !strings.Contains(drawn, eyesMark) {
+		add(CheckEyes, SeverityWarning, 0, 0, "no {{.Eyes}} placeholder; moods will not change the cow's eyes")
+	}
+	if !strings.Contains(drawn, tongueMark) {
+		add(CheckTongue, SeverityWarning, 0, 0, "no {{.Tongue}} placeholder; moods will not change the cow's tongue")
+	}
+
+	var prevLine, prevCol int
+	for i, line := range strings.Split(strings.TrimSuffix(drawn, "\n"), "\n") {
+		if w := runewidth.StringWidth(unmark.Replace(line)); w > width {
+			add(CheckWidth, SeverityWarning, i+1, 0, "line is %d columns wide, more than %d", w, width)
+		}
+		for rest, offset := line, 0; ; {
+			j := strings.Index(rest, thoughtsMark)
+			if j < 0 {
+				break
+			}
+			row, col := i+1, runewidth.StringWidth(unmark.Replace(line[:offset+j]))+1
+			switch {
+			case prevLine == 0 && row != 1:
+				add(CheckThoughts, SeverityError, row, col, "connector starts on line %d; it must start on line 1, just below the balloon", row)
+			case prevLine != 0 && (row != prevLine+1 || col-prevCol < 1 || col-prevCol > 2):
+				add(CheckThoughts, SeverityError, row, col, "connector does not follow on from the one on line %d, column %d", prevLine, prevCol)
+			}
+			prevLine, prevCol = row, col
+			rest, offset = rest[j+1:], offset+j+1
+		}
+	}
+	if prevLine == 0 {
+		add(CheckThoughts, SeverityError, 0, 0, "no {{.Thoughts}} connector; the balloon is not joined to the cow")
+	}
+	return issues
+}
+
+// ending names a line ending
+func ending(crlf bool) string {
+	if crlf {
+		return "CRLF"
+	}
+	return "LF"
+}
@@ -0,0 +1,91 @@
+package cowfile
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/vnykmshr/gowsay/cow"
+)
+
+func TestLint(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want []Issue
+	}{
+		{"clean", "  {{.Thoughts}}\n   {{.Thoughts}} ({{.Eyes}})\n      {{.Tongue}}\n", nil},
+		{"tail", "{{.Thoughts}} ({{.Eyes}}){{.Tail}}\n {{.Thoughts}} {{.Tongue}}\n", nil},
+		{"no placeholders", "  {{.Thoughts}}\n   {{.Thoughts}} (oo)\n", []Issue{
+			{Check: CheckEyes, Severity: SeverityWarning},
+			{Check: CheckTongue, Severity: SeverityWarning},
+		}},
+		{"parse error", "{{.Thoughts}} {{.Eyes}\n", []Issue{
+			{Check: CheckTemplate, Severity: SeverityError},
+		}},
+		{"unknown field", "{{.Thoughts}} {{.Horns}}\n", []Issue{
+			{Check: CheckTemplate, Severity: SeverityError},
+		}},
+		{"no connector", "({{.Eyes}}) {{.Tongue}}\n", []Issue{
+			{Check: CheckThoughts, Severity: SeverityError},
+		}},
+		{"detached", "\n  {{.Thoughts}} ({{.Eyes}}) {{.Tongue}}\n", []Issue{
+			{Check: CheckThoughts, Severity: SeverityError, Line: 2, Column: 3},
+		}},
+		{"broken chain", "  {{.Thoughts}} ({{.Eyes}}) {{.Tongue}}\n {{.Thoughts}}\n", []Issue{
+			{Check: CheckThoughts, Severity: SeverityError, Line: 2, Column: 2},
+		}},
+		{"eyes before connector", "{{.Eyes}}{{.Thoughts}}\n    {{.Thoughts}} {{.Tongue}}\n", nil},
+		{"whitespace", "{{.Thoughts}} ({{.Eyes}})  \n\t {{.Thoughts}}{{.Tongue}}\n", []Issue{
+			{Check: CheckWhitespace, Severity: SeverityWarning, Line: 1, Column: 26},
+			{Check: CheckTabs, Severity: SeverityError, Line: 2, Column: 1},
+		}},
+		{"line endings", "{{.Thoughts}} ({{.Eyes}})\r\n {{.Thoughts}}\n  {{.Tongue}}\r\n", []Issue{
+			{Check: CheckLineEndings, Severity: SeverityError, Line: 2},
+		}},
+		{"crlf", "{{.Thoughts}} ({{.Eyes}})\r\n {{.Thoughts}} {{.Tongue}}\r\n", nil},
+		{"width", "{{.Thoughts}} ({{.Eyes}}) {{.Tongue}} ---------\n", []Issue{
+			{Check: CheckWidth, Severity: SeverityWarning, Line: 1},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Lint(tt.src, 16)
+			// Messages are for people; compare everything else
+			for i := range got {
+				got[i].Message = ""
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Lint(%q) = %+v, want %+v", tt.src, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewReport(t *testing.T) {
+	if r := NewReport("ok.cow", "{{.Thoughts}} {{.Eyes}}\n", LintWidth); !r.Valid || len(r.Issues) != 1 {
+		t.Errorf("NewReport() = %+v, want valid with a tongue warning", r)
+	}
+	if r := NewReport("bad.cow", "{{.Eyes}}\n", LintWidth); r.Valid {
+		t.Errorf("NewReport() = %+v, want invalid", r)
+	}
+}
+
+// knownIssues are the checks built-in cows fail on purpose
+var knownIssues = map[string][]string{
+	// The mech stands beside the balloon instead of under it
+	"mech-and-cow": {CheckThoughts},
+}
+
+func TestLint_BuiltinCows(t *testing.T) {
+	for _, name := range cow.List() {
+		src, ok := cow.Template(name)
+		if !ok {
+			continue
+		}
+		for _, issue := range Lint(src, LintWidth) {
+			if issue.Severity == SeverityError && !slices.Contains(knownIssues[name], issue.Check) {
+				t.Errorf("cow %q: %s line %d column %d: %s", name, issue.Check, issue.Line, issue.Column, issue.Message)
+			}
+		}
+	}
+}
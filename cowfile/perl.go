@@ -0,0 +1,175 @@
+package cowfile
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/vnykmshr/gowsay/cow"
+)
+
+// perlVars are the variables classic cowsay interpolates into $the_cow,
+// and the placeholders they stand for. ToPerl marks each one in the art
+// with its index.
+var perlVars = [...]struct{ name, placeholder string }{
+	{"thoughts", thoughts},
+	{"eyes", eyes},
+	{"tongue", tongue},
+}
+
+// heredoc matches the start of a $the_cow here document, with its
+// terminator double quoted, single quoted or bare
+var heredoc = regexp.MustCompile(`\$the_cow\s*=\s*<<\s*(?:"(\w+)"|'(\w+)'|(\w+))\s*;[^\n]*\n`)
+
+// perlVar matches a scalar variable at the start of interpolated text
+var perlVar = regexp.MustCompile(`^\$(?:(\w+)|\{(\w+)\})`)
+
+// ToPerl converts a cow template into a classic Perl cowfile, which sets
+// $the_cow in a here document that interpolates $thoughts, $eyes and
+// $tongue. Perl has no tail to wag, so a {{.Tail}} placeholder is drawn
+// as tail.
+func ToPerl(src, tail string) (string, error) {
+	tmpl, err := template.New("cow").Parse(src)
+	if err != nil {
+		return "", err
+	}
+	var out strings.Builder
+	if err := tmpl.Execute(&out, &cow.Face{Thoughts: "\x00", Eyes: "\x01", Tongue: "\x02", Tail: tail}); err != nil {
+		return "", err
+	}
+	art := out.String()
+	if !strings.HasSuffix(art, "\n") {
+		art += "\n"
+	}
+
+	for i, line := range strings.Split(strings.TrimSuffix(art, "\n"), "\n") {
+		if line == "EOC" {
+			return "", fmt.Errorf("line %d is the here document terminator EOC", i+1)
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString("$the_cow = <<\"EOC\";\n")
+	for i := 0; i < len(art); i++ {
+		c := art[i]
+		var next byte
+		if i+1 < len(art) {
+			next = art[i+1]
+		}
+		afterVar := i > 0 && art[i-1] < byte(len(perlVars))
+		switch {
+		case c < byte(len(perlVars)):
+			// Braces keep Perl from reading on into a longer name
+			if name := perlVars[c].name; isWord(next) || next == ':' {
+				sb.WriteString("${" + name + "}")
+			} else {
+				sb.WriteString("$" + name)
+			}
+		case c == '\\' || c == '$' || c == '@',
+			// Subscripts and arrows after a variable would index it
+			afterVar && (c == '[' || c == '{' || c == '-' && next == '>'):
+			sb.WriteByte('\\')
+			sb.WriteByte(c)
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	sb.WriteString("EOC\n")
+	return sb.String(), nil
+}
+
+// FromPerl converts a classic Perl cowfile into a cow template. Only the
+// $the_cow here document is read; other Perl code is ignored, and
+// variables other than $thoughts, $eyes and $tongue are an error.
+func FromPerl(src string) (string, error) {
+	src = strings.ReplaceAll(src, "\r\n", "\n")
+	m := heredoc.FindStringSubmatchIndex(src)
+	if m == nil {
+		return "", errors.New("no $the_cow here document")
+	}
+	var term string
+	for g := 1; g <= 3; g++ {
+		if m[2*g] >= 0 {
+			term = src[m[2*g]:m[2*g+1]]
+		}
+	}
+	var doc strings.Builder
+	closed := false
+	for _, l := range strings.SplitAfter(src[m[1]:], "\n") {
+		if strings.TrimSuffix(l, "\n") == term {
+			closed = true
+			break
+		}
+		doc.WriteString(l)
+	}
+	if !closed {
+		return "", fmt.Errorf("here document is not closed by %s", term)
+	}
+	body := doc.String()
+
+	// Single quoted here documents are not interpolated
+	if m[4] >= 0 {
+		return literal(body), nil
+	}
+
+	var sb, lit strings.Builder
+	line := 1
+	for i := 0; i < len(body); {
+		c := body[i]
+		switch {
+		case c == '\\' && i+1 < len(body):
+			switch e := body[i+1]; e {
+			case 'n':
+				lit.WriteByte('\n')
+			case 't':
+				lit.WriteByte('\t')
+			default:
+				lit.WriteByte(e)
+			}
+			i += 2
+			continue
+		case c == '$':
+			if v := perlVar.FindStringSubmatch(body[i:]); v != nil {
+				name := v[1] + v[2]
+				placeholder := ""
+				for _, pv := range perlVars {
+					if pv.name == name {
+						placeholder = pv.placeholder
+					}
+				}
+				if placeholder == "" {
+					return "", fmt.Errorf("line %d: variable $%s is not supported", line, name)
+				}
+				sb.WriteString(literal(lit.String()))
+				lit.Reset()
+				sb.WriteString(placeholder)
+				i += len(v[0])
+				continue
+			}
+		case c == '\n':
+			line++
+		}
+		lit.WriteByte(c)
+		i++
+	}
+	sb.WriteString(literal(lit.String()))
+	return sb.String(), nil
+}
+
+// literal escapes text for a template, so braces in the art are not read
+// as actions. A trailing brace is escaped too, in case a placeholder
+// follows it.
+func literal(s string) string {
+	s = strings.ReplaceAll(s, "{{", `{{"{{"}}`)
+	if strings.HasSuffix(s, "{") {
+		s = strings.TrimSuffix(s, "{") + `{{"{"}}`
+	}
+	return s
+}
+
+// isWord reports whether c can continue a Perl variable name
+func isWord(c byte) bool {
+	return c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
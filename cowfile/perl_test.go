@@ -0,0 +1,167 @@
+package cowfile
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/vnykmshr/gowsay/cow"
+)
+
+// classicDefault is the default cow as classic cowsay ships it
+const classicDefault = `$the_cow = <<"EOC";
+        $thoughts   ^__^
+         $thoughts  ($eyes)\\_______
+            (__)\\       )\\/\\
+             $tongue ||----w |
+                ||     ||
+EOC
+`
+
+func TestToPerl(t *testing.T) {
+	src, _ := cow.Template("default")
+
+	tests := []struct {
+		name string
+		src  string
+		tail string
+		want string
+	}{
+		{"default", src, cow.Tail("default"), classicDefault},
+		{"escapes", "{{.Thoughts}} $5 @home \\o/\n", "", "$the_cow = <<\"EOC\";\n$thoughts \\$5 \\@home \\\\o/\nEOC\n"},
+		{"braces", "{{.Eyes}}x {{.Eyes}}[0] {{.Eyes}}->{{.Tongue}}\n", "", "$the_cow = <<\"EOC\";\n${eyes}x $eyes\\[0] $eyes\\->$tongue\nEOC\n"},
+		{"no final newline", "{{.Thoughts}}", "", "$the_cow = <<\"EOC\";\n$thoughts\nEOC\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ToPerl(tt.src, tt.tail)
+			if err != nil {
+				t.Fatalf("ToPerl() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ToPerl() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := ToPerl("{{.Thoughts}}\nEOC\n", ""); err == nil {
+		t.Error("ToPerl() with an EOC line succeeded, want error")
+	}
+}
+
+func TestFromPerl(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{"classic", "##\n## The default cow\n##\n" + classicDefault,
+			"        {{.Thoughts}}   ^__^\n         {{.Thoughts}}  ({{.Eyes}})\\_______\n            (__)\\       )\\/\\\n             {{.Tongue}} ||----w |\n                ||     ||\n"},
+		{"bare terminator", "$the_cow = <<EOC;\n${thoughts}_ \\$\\@\nEOC", "{{.Thoughts}}_ $@\n"},
+		{"crlf", "$the_cow = << \"COW\";\r\n$thoughts ($eyes)\r\nCOW\r\n", "{{.Thoughts}} ({{.Eyes}})\n"},
+		{"single quoted", "$the_cow = <<'EOC';\n$thoughts \\o/\nEOC\n", "$thoughts \\o/\n"},
+		{"braces", "$the_cow = <<EOC;\n{{ {$eyes}\nEOC\n", "{{\"{{\"}} {{\"{\"}}{{.Eyes}}}\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FromPerl(tt.src)
+			if err != nil {
+				t.Fatalf("FromPerl() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("FromPerl() = %q, want %q", got, tt.want)
+			}
+			if _, err := template.New("cow").Parse(got); err != nil {
+				t.Errorf("FromPerl() = %q does not parse: %v", got, err)
+			}
+		})
+	}
+}
+
+func TestFromPerl_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{"no here document", `$the_cow = "moo";`, "no $the_cow here document"},
+		{"not closed", "$the_cow = <<EOC;\n$thoughts\n", "here document is not closed by EOC"},
+		{"variable", "$the_cow = <<EOC;\n$thoughts\n $extra\nEOC\n", "line 2: variable $extra is not supported"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := FromPerl(tt.src); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("FromPerl() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+// TestPerl_RoundTrip exports every built-in cow and imports it again; the
+// template comes back unchanged, except for a tail at rest
+func TestPerl_RoundTrip(t *testing.T) {
+	for _, name := range cow.List() {
+		src, ok := cow.Template(name)
+		if !ok {
+			continue
+		}
+		perl, err := ToPerl(src, cow.Tail(name))
+		if err != nil {
+			t.Errorf("ToPerl(%s) error = %v", name, err)
+			continue
+		}
+		got, err := FromPerl(perl)
+		if err != nil {
+			t.Errorf("FromPerl(ToPerl(%s)) error = %v", name, err)
+			continue
+		}
+		if want := strings.ReplaceAll(src, "{{.Tail}}", cow.Tail(name)); got != want {
+			t.Errorf("FromPerl(ToPerl(%s)) =\n%s\nwant\n%s", name, got, want)
+		}
+	}
+}
+
+// TestToPerl_Perl runs the exported cowfiles in Perl, the way classic
+// cowsay does, and checks they draw the same cow
+func TestToPerl_Perl(t *testing.T) {
+	perl, err := exec.LookPath("perl")
+	if err != nil {
+		t.Skip("perl not found")
+	}
+	face := cow.Face{Thoughts: `\`, Eyes: "Oo", Tongue: "U "}
+	dir := t.TempDir()
+	for _, name := range cow.List() {
+		src, ok := cow.Template(name)
+		if !ok {
+			continue
+		}
+		exported, err := ToPerl(src, cow.Tail(name))
+		if err != nil {
+			t.Fatalf("ToPerl(%s) error = %v", name, err)
+		}
+		file := filepath.Join(dir, name+".cow")
+		if err := os.WriteFile(file, []byte(exported), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		script := `our ($thoughts, $eyes, $tongue, $the_cow) = @ARGV; do $ARGV[3] or die $@ || $!; print $the_cow`
+		out, err := exec.Command(perl, "-e", script, face.Thoughts, face.Eyes, face.Tongue, file).Output()
+		if err != nil {
+			t.Errorf("perl %s.cow error = %v", name, err)
+			continue
+		}
+
+		var want strings.Builder
+		f := face
+		f.Tail = cow.Tail(name)
+		if err := template.Must(template.New(name).Parse(src)).Execute(&want, &f); err != nil {
+			t.Fatal(err)
+		}
+		if string(out) != want.String() {
+			t.Errorf("perl %s.cow =\n%s\nwant\n%s", name, out, want.String())
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/vnykmshr/gowsay/cow"
+	"github.com/vnykmshr/gowsay/cowfile"
+)
+
+func TestRunCowfile_FromImage(t *testing.T) {
+	dir := t.TempDir()
+	img := image.NewGray(image.Rect(0, 0, 8, 4))
+	for i := range img.Pix {
+		img.Pix[i] = 0xff
+	}
+	img.SetGray(2, 0, color.Gray{})
+	img.SetGray(2, 1, color.Gray{})
+	path := filepath.Join(dir, "dot.png")
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := runCowfile([]string{"from-image", "-w", "8", "-thoughts", "0,0", "-eyes", "4, 0", path}, &out); err != nil {
+		t.Fatalf("runCowfile() error = %v", err)
+	}
+	if want := "{{.Thoughts}} @ {{.Eyes}}\n"; out.String() != want {
+		t.Errorf("runCowfile(from-image) = %q, want %q", out.String(), want)
+	}
+
+	written := filepath.Join(dir, "dot.cow")
+	if err := runCowfile([]string{"from-image", "-w", "8", "-o", written, path}, &out); err != nil {
+		t.Fatalf("runCowfile(-o) error = %v", err)
+	}
+	if data, err := os.ReadFile(written); err != nil || !strings.Contains(string(data), "{{.Thoughts}}") {
+		t.Errorf("runCowfile(-o) wrote %q, %v", data, err)
+	}
+}
+
+func TestRunCowfile_Lint(t *testing.T) {
+	dir := t.TempDir()
+	good := filepath.Join(dir, "good.cow")
+	bad := filepath.Join(dir, "bad.cow")
+	if err := os.WriteFile(good, []byte("  {{.Thoughts}}\n   {{.Thoughts}} ({{.Eyes}}) {{.Tongue}}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(bad, []byte("({{.Eyes}})\t\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := runCowfile([]string{"lint", good}, &out); err != nil {
+		t.Fatalf("runCowfile(lint) error = %v", err)
+	}
+	var reports []cowfile.Report
+	if err := json.Unmarshal(out.Bytes(), &reports); err != nil {
+		t.Fatalf("runCowfile(lint) wrote %q: %v", out.String(), err)
+	}
+	if len(reports) != 1 || !reports[0].Valid || reports[0].File != good || len(reports[0].Issues) != 0 {
+		t.Errorf("runCowfile(lint) = %+v, want one valid report", reports)
+	}
+
+	out.Reset()
+	err := runCowfile([]string{"lint", good, bad}, &out)
+	if err == nil || !strings.Contains(err.Error(), "cowfile lint found 2 errors") {
+		t.Errorf("runCowfile(lint) error = %v, want 2 errors", err)
+	}
+	reports = nil
+	if err := json.Unmarshal(out.Bytes(), &reports); err != nil || len(reports) != 2 || reports[1].Valid {
+		t.Errorf("runCowfile(lint) = %+v, %v, want the bad cowfile invalid", reports, err)
+	}
+}
+
+func TestRunCowfile_Export(t *testing.T) {
+	var out bytes.Buffer
+	if err := runCowfile([]string{"export", "default"}, &out); err != nil {
+		t.Fatalf("runCowfile(export) error = %v", err)
+	}
+	if !strings.HasPrefix(out.String(), "##\n## The classic cowsay cow\n") || !strings.Contains(out.String(), "$the_cow = <<\"EOC\";\n") {
+		t.Errorf("runCowfile(export) = %q, want a Perl cowfile", out.String())
+	}
+
+	// Flags may follow the names
+	dir := filepath.Join(t.TempDir(), "out")
+	if err := runCowfile([]string{"export", "tux", "--dir", dir}, &out); err != nil {
+		t.Fatalf("runCowfile(export --dir) error = %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "tux.cow"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if src, err := cowfile.FromPerl(string(data)); err != nil || !strings.Contains(src, "{{.Thoughts}}") {
+		t.Errorf("exported tux.cow imports as %q, %v", src, err)
+	}
+
+	if err := runCowfile([]string{"export", "--all", "--dir", dir}, &out); err != nil {
+		t.Fatalf("runCowfile(export --all) error = %v", err)
+	}
+	files, _ := filepath.Glob(filepath.Join(dir, "*.cow"))
+	want := 0
+	for _, name := range cow.List() {
+		if cow.Exists(name) {
+			want++
+		}
+	}
+	if len(files) != want {
+		t.Errorf("runCowfile(export --all) wrote %d files, want %d", len(files), want)
+	}
+}
+
+func TestRunCowfile_Import(t *testing.T) {
+	dir := t.TempDir()
+	var perl bytes.Buffer
+	if err := runCowfile([]string{"export", "default"}, &perl); err != nil {
+		t.Fatal(err)
+	}
+	file := filepath.Join(dir, "default.cow")
+	if err := os.WriteFile(file, perl.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := runCowfile([]string{"import", file}, &out); err != nil {
+		t.Fatalf("runCowfile(import) error = %v", err)
+	}
+	if r := cowfile.NewReport(file, out.String(), cowfile.LintWidth); !strings.Contains(out.String(), "{{.Eyes}}") || len(r.Issues) > 0 {
+		t.Errorf("runCowfile(import) = %q, issues %v, want a clean template", out.String(), r.Issues)
+	}
+
+	outFile := filepath.Join(dir, "default.tmpl")
+	if err := runCowfile([]string{"import", "-o", outFile, file}, &bytes.Buffer{}); err != nil {
+		t.Fatalf("runCowfile(import -o) error = %v", err)
+	}
+	if data, err := os.ReadFile(outFile); err != nil || string(data) != out.String() {
+		t.Errorf("runCowfile(import -o) wrote %q, %v, want %q", data, err, out.String())
+	}
+}
+
+func TestRunCowfile_Errors(t *testing.T) {
+	notImage := filepath.Join(t.TempDir(), "moo.txt")
+	if err := os.WriteFile(notImage, []byte("moo"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"unknown command", []string{"draw"}, "cowfile command 'draw' not found"},
+		{"unknown cow", []string{"export", "default", "nope"}, "cow 'nope' not found"},
+		{"bad position", []string{"from-image", "-eyes", "3", notImage}, "position '3' must be column,row"},
+		{"bad style", []string{"from-image", "-style", "sixel", notImage}, "style 'sixel' must be ascii or blocks"},
+		{"bad width", []string{"from-image", "-w", "0", notImage}, "width must be between 1 and 200"},
+		{"not an image", []string{"from-image", notImage}, "is not a PNG or JPEG image"},
+		{"not a perl cowfile", []string{"import", notImage}, "moo.txt: "},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := runCowfile(tt.args, &bytes.Buffer{})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("runCowfile(%v) error = %v, want %q", tt.args, err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/vnykmshr/gowsay/cow"
+	"github.com/vnykmshr/gowsay/fortune"
+	"github.com/vnykmshr/gowsay/locale"
+)
+
+// runDaily implements the "daily" subcommand: the cow of the day
+func runDaily(args []string, w io.Writer) error {
+	fs := flag.NewFlagSet("daily", flag.ContinueOnError)
+	var (
+		namespace = fs.String("namespace", "", "Namespace, so different teams get different cows")
+		tz        = fs.String("tz", os.Getenv("GOWSAY_TIMEZONE"), "Time zone that decides when the day changes (default: local)")
+		date      = fs.String("date", "", "Show the cow of another day (YYYY-MM-DD)")
+		columns   = fs.Int("w", 40, "Column width for text wrapping")
+		safe      = fs.Bool("safe", envBool("GOWSAY_SAFE_MODE"), "Safe mode: hide cows not rated safe")
+	)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage:\n  gowsay daily [options]\n\nOptions:\n")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	loc := time.Local
+	if *tz != "" {
+		var err error
+		if loc, err = time.LoadLocation(*tz); err != nil {
+			return fmt.Errorf("time zone: %w", err)
+		}
+	}
+
+	day := time.Now().In(loc)
+	if *date != "" {
+		var err error
+		if day, err = time.ParseInLocation("2006-01-02", *date, loc); err != nil {
+			return fmt.Errorf("date: %w", err)
+		}
+	}
+
+	weights, err := cow.ParseWeights(os.Getenv("GOWSAY_WEIGHTS"))
+	if err != nil {
+		return err
+	}
+	// Messages come from the same fortune packs as /api/daily, or the
+	// built-in moo messages
+	packs, err := fortunePacks(os.Getenv("GOWSAY_FORTUNE_PATH"), locale.NewPrinter(locale.FromEnv()))
+	if err != nil {
+		return err
+	}
+	sel := cow.Selector{
+		SafeMode: *safe,
+		Weights:  weights,
+		Exclude:  cow.ParseList(os.Getenv("GOWSAY_EXCLUDE")),
+		Messages: fortune.Messages(packs),
+	}
+
+	daily := sel.Daily(day, *namespace)
+	_, err = fmt.Fprint(w, cow.Render([]string{daily.Message}, daily.Cow, "", cow.ActionSay, *columns))
+	return err
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunDaily(t *testing.T) {
+	var a, b bytes.Buffer
+	if err := runDaily([]string{"-date", "2026-10-19", "-tz", "UTC"}, &a); err != nil {
+		t.Fatalf("runDaily() error = %v", err)
+	}
+	if err := runDaily([]string{"-date", "2026-10-19", "-tz", "UTC"}, &b); err != nil {
+		t.Fatalf("runDaily() error = %v", err)
+	}
+	if a.String() != b.String() {
+		t.Error("same date should render the same cow of the day")
+	}
+	if !strings.Contains(a.String(), "\\") {
+		t.Error("output should contain a cow")
+	}
+}
+
+func TestRunDaily_FortunePath(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "barn"), []byte("Only fortune in the barn\n%\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GOWSAY_FORTUNE_PATH", dir)
+
+	var out bytes.Buffer
+	if err := runDaily([]string{"-date", "2026-10-19", "-tz", "UTC"}, &out); err != nil {
+		t.Fatalf("runDaily() error = %v", err)
+	}
+	if !strings.Contains(out.String(), "Only fortune in the barn") {
+		t.Errorf("runDaily() should say the fortune from GOWSAY_FORTUNE_PATH, got:\n%s", out.String())
+	}
+
+	t.Setenv("GOWSAY_FORTUNE_PATH", filepath.Join(dir, "nope"))
+	if err := runDaily(nil, &out); err == nil {
+		t.Error("runDaily() with a missing GOWSAY_FORTUNE_PATH should fail")
+	}
+}
+
+func TestRunDaily_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"bad date", []string{"-date", "yesterday"}},
+		{"bad time zone", []string{"-tz", "Nowhere/Special"}},
+		{"unknown flag", []string{"-nope"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			if err := runDaily(tt.args, &out); err == nil {
+				t.Errorf("runDaily(%v) should fail", tt.args)
+			}
+		})
+	}
+}
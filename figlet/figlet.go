@@ -0,0 +1,247 @@
+package figlet
+
+import (
+	"bufio"
+	"embed"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+//go:embed fonts/*.flf
+var fontFiles embed.FS
+
+// DefaultFont is the embedded font used when none is named
+const DefaultFont = "small"
+
+// fontExt is the file extension of FIGlet fonts
+const fontExt = ".flf"
+
+// Layout bits of the header's full_layout field
+const (
+	smushEqual     = 1
+	smushLowline   = 2
+	smushHierarchy = 4
+	smushPair      = 8
+	smushBigX      = 16
+	smushHardblank = 32
+	layoutKern     = 64
+	layoutSmush    = 128
+)
+
+// deutsch are the code points of the required characters that follow
+// ASCII 32-126 in every font
+var deutsch = []rune{196, 214, 220, 228, 246, 252, 223}
+
+// Font is a FIGlet font
+type Font struct {
+	Name      string
+	Height    int
+	Baseline  int
+	hardblank rune
+	layout    int
+	glyphs    map[rune][][]rune
+}
+
+// Parse reads a font in the FIGlet 2 (.flf) format
+func Parse(r io.Reader) (*Font, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	if !scanner.Scan() {
+		return nil, errors.New("figlet: empty font")
+	}
+	header := strings.Fields(scanner.Text())
+	if len(header) < 6 || !strings.HasPrefix(header[0], "flf2a") || utf8.RuneCountInString(header[0]) != 6 {
+		return nil, errors.New("figlet: not a FIGlet 2 font")
+	}
+
+	var params [8]int
+	for i := 1; i < len(header) && i < len(params); i++ {
+		n, err := strconv.Atoi(header[i])
+		if err != nil {
+			return nil, fmt.Errorf("figlet: header field %d: %w", i, err)
+		}
+		params[i] = n
+	}
+	height, baseline, oldLayout, comments := params[1], params[2], params[4], params[5]
+	if height < 1 || height > 64 {
+		return nil, fmt.Errorf("figlet: bad height %d", height)
+	}
+
+	f := &Font{
+		Height:    height,
+		Baseline:  baseline,
+		hardblank: []rune(header[0])[5],
+		glyphs:    make(map[rune][][]rune),
+	}
+
+	// full_layout is optional; without it, old_layout decides
+	if len(header) > 7 {
+		f.layout = params[7]
+	} else {
+		switch {
+		case oldLayout < 0:
+			f.layout = 0
+		case oldLayout == 0:
+			f.layout = layoutKern
+		default:
+			f.layout = oldLayout&63 | layoutSmush
+		}
+	}
+
+	for i := 0; i < comments; i++ {
+		if !scanner.Scan() {
+			return nil, errors.New("figlet: truncated comments")
+		}
+	}
+
+	readGlyph := func() ([][]rune, error) {
+		rows := make([][]rune, height)
+		for i := range rows {
+			if !scanner.Scan() {
+				if err := scanner.Err(); err != nil {
+					return nil, err
+				}
+				return nil, io.ErrUnexpectedEOF
+			}
+			rows[i] = trimEndmark([]rune(strings.TrimRight(scanner.Text(), "\r")))
+		}
+		return rows, nil
+	}
+
+	// Required characters: ASCII, then the Deutsch characters, which
+	// older fonts may leave out
+	for code := rune(32); code <= 126; code++ {
+		rows, err := readGlyph()
+		if err != nil {
+			return nil, fmt.Errorf("figlet: character %d: %w", code, err)
+		}
+		f.glyphs[code] = rows
+	}
+	for _, code := range deutsch {
+		rows, err := readGlyph()
+		if errors.Is(err, io.ErrUnexpectedEOF) {
+			return f, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("figlet: character %d: %w", code, err)
+		}
+		f.glyphs[code] = rows
+	}
+
+	// Code-tagged characters: a line with the code, then the glyph
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		code, err := strconv.ParseInt(fields[0], 0, 32)
+		if err != nil {
+			return nil, fmt.Errorf("figlet: bad character code %q", fields[0])
+		}
+		rows, err := readGlyph()
+		if err != nil {
+			return nil, fmt.Errorf("figlet: character %d: %w", code, err)
+		}
+		if code >= 0 {
+			f.glyphs[rune(code)] = rows
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// Load reads a font file from disk
+func Load(file string) (*Font, error) {
+	r, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	f, err := Parse(r)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+	f.Name = strings.TrimSuffix(filepath.Base(file), fontExt)
+	return f, nil
+}
+
+// LoadDir reads every .flf font in dir
+func LoadDir(dir string) ([]*Font, error) {
+	matches, err := filepath.Glob(filepath.Join(dir, "*"+fontExt))
+	if err != nil {
+		return nil, err
+	}
+	fonts := make([]*Font, 0, len(matches))
+	for _, file := range matches {
+		f, err := Load(file)
+		if err != nil {
+			return nil, err
+		}
+		fonts = append(fonts, f)
+	}
+	return fonts, nil
+}
+
+// builtins parses the embedded fonts once. Fonts are not modified after
+// parsing, so they are safe to share.
+var builtins = sync.OnceValue(func() map[string]*Font {
+	entries, _ := fs.ReadDir(fontFiles, "fonts")
+	fonts := make(map[string]*Font, len(entries))
+	for _, entry := range entries {
+		r, err := fontFiles.Open(path.Join("fonts", entry.Name()))
+		if err != nil {
+			panic(err)
+		}
+		f, err := Parse(r)
+		r.Close()
+		if err != nil {
+			panic(fmt.Sprintf("figlet: embedded font %s: %v", entry.Name(), err))
+		}
+		f.Name = strings.TrimSuffix(entry.Name(), fontExt)
+		fonts[f.Name] = f
+	}
+	return fonts
+})
+
+// Builtin returns the embedded font with the given name
+func Builtin(name string) (*Font, bool) {
+	f, ok := builtins()[name]
+	return f, ok
+}
+
+// Builtins returns the names of the embedded fonts, sorted
+func Builtins() []string {
+	names := make([]string, 0, len(builtins()))
+	for name := range builtins() {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// trimEndmark removes the endmark, the last character of a glyph line,
+// and any repeats of it
+func trimEndmark(line []rune) []rune {
+	if len(line) == 0 {
+		return line
+	}
+	end := line[len(line)-1]
+	for len(line) > 0 && line[len(line)-1] == end {
+		line = line[:len(line)-1]
+	}
+	return line
+}
@@ -0,0 +1,170 @@
+package figlet
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// testFont builds a one-line-high font from the header's layout fields and
+// a few glyphs; every other character is blank. Extra lines are appended
+// after the required characters, for code-tagged characters.
+func testFont(layout string, glyphs map[rune]string, extra ...string) string {
+	lines := []string{"flf2a$ 1 1 8 " + layout, "test font"}
+	for code := rune(32); code <= 126; code++ {
+		lines = append(lines, glyphs[code]+"@@")
+	}
+	for _, code := range deutsch {
+		lines = append(lines, glyphs[code]+"@@")
+	}
+	lines = append(lines, extra...)
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func parse(t *testing.T, text string) *Font {
+	t.Helper()
+	f, err := Parse(strings.NewReader(text))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	return f
+}
+
+func TestRender_Layout(t *testing.T) {
+	glyphs := map[rune]string{
+		'H': "|-|", 'I': " |", 'S': "/ ", 'L': " [", 'R': "] ",
+		'F': " /", 'B': `\ `, 'X': "x$", ' ': "$$",
+	}
+
+	tests := []struct {
+		name   string
+		layout string
+		text   string
+		want   string
+	}{
+		{"full width", "-1 1 0 0 0", "HH", "|-||-|"},
+		{"kerning", "0 1", "HH", "|-||-|"},
+		{"kerning closes gaps", "0 1", "HIS", "|-||/"},
+		{"universal smushing", "0 1 0 128 0", "HH", "|-|-|"},
+		{"equal", "1 1", "HH", "|-|-|"},
+		{"equal does not smush others", "1 1", "HIS", "|-|/"},
+		{"hierarchy", "4 1", "HIS", "|-|/"},
+		{"pair", "8 1", "HLR", "|-||"},
+		{"big x", "16 1", "HFB", "|-||"},
+		{"hardblanks stay apart", "1 1", "X X", "x   x"},
+		{"hardblank rule", "32 1", "X X", "x  x"},
+		{"missing characters are skipped", "-1 1", "H☺H", "|-||-|"},
+		{"leading blank columns are dropped", "0 1", "IS", "|/"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := parse(t, testFont(tt.layout, glyphs))
+			got := f.Render(tt.text)
+			if len(got) != 1 || got[0] != tt.want {
+				t.Errorf("Render(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse_CodeTagged(t *testing.T) {
+	f := parse(t, testFont("-1 1", map[rune]string{'A': "a", 'Ä': "ae"},
+		"9786 WHITE SMILING FACE", ":)@",
+		"0x263B  BLACK SMILING FACE", ":D##",
+		"-1 negative codes are not characters", "nope@"))
+
+	if got := f.Render("A☺☻"); got[0] != "a:):D" {
+		t.Errorf("Render() = %q, want a:):D", got)
+	}
+	if got := f.Render("Ä"); got[0] != "ae" {
+		t.Errorf("Render(Ä) = %q, want ae", got)
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+	}{
+		{"empty", ""},
+		{"not a font", "hello world\n"},
+		{"bad height", "flf2a$ 0 0 8 -1 0\n"},
+		{"truncated", "flf2a$ 1 1 8 -1 0\n@@\n"},
+		{"bad code tag", testFont("-1 1", nil, "nope", "x@")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse(strings.NewReader(tt.text)); err == nil {
+				t.Errorf("Parse(%s) should fail", tt.name)
+			}
+		})
+	}
+}
+
+func TestBuiltins(t *testing.T) {
+	names := Builtins()
+	for _, want := range []string{"block", "mini", DefaultFont} {
+		if !slices.Contains(names, want) {
+			t.Errorf("Builtins() = %v, missing %s", names, want)
+		}
+	}
+
+	for _, name := range names {
+		f, ok := Builtin(name)
+		if !ok {
+			t.Fatalf("Builtin(%s) not found", name)
+		}
+		lines := f.Render("Moo 1.0!\nok")
+		if len(lines) != 2*f.Height {
+			t.Errorf("%s: Render() has %d lines, want %d", name, len(lines), 2*f.Height)
+		}
+		if strings.Contains(strings.Join(lines, ""), "$") {
+			t.Errorf("%s: hardblanks should render as spaces", name)
+		}
+	}
+
+	if _, ok := Builtin("nope"); ok {
+		t.Error("Builtin(nope) should not be found")
+	}
+}
+
+func TestRender_Small(t *testing.T) {
+	f, _ := Builtin("small")
+	want := []string{
+		"# # ###",
+		"# #  #",
+		"###  #",
+		"# #  #",
+		"# # ###",
+	}
+	if got := f.Render("hi"); !slices.Equal(got, want) {
+		t.Errorf("Render(hi) =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestLoadDir(t *testing.T) {
+	dir := t.TempDir()
+	font := testFont("-1 1", map[rune]string{'A': "a"})
+	if err := os.WriteFile(filepath.Join(dir, "tiny.flf"), []byte(font), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "README"), []byte("not a font"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	fonts, err := LoadDir(dir)
+	if err != nil {
+		t.Fatalf("LoadDir() error = %v", err)
+	}
+	if len(fonts) != 1 || fonts[0].Name != "tiny" {
+		t.Fatalf("LoadDir() = %v, want the tiny font", fonts)
+	}
+
+	if _, err := Load(filepath.Join(dir, "missing.flf")); err == nil {
+		t.Error("Load() of a missing file should fail")
+	}
+}
@@ -0,0 +1,169 @@
+package figlet
+
+import (
+	"strings"
+)
+
+// Render draws text in the font and returns its lines. Newlines in text
+// start a new row of letters. Characters missing from the font are
+// skipped.
+func (f *Font) Render(text string) []string {
+	var lines []string
+	for _, row := range strings.Split(text, "\n") {
+		lines = append(lines, f.renderRow(row)...)
+	}
+	return lines
+}
+
+// renderRow draws one row of letters, smushing or fitting each character
+// into the output as the font's layout asks
+func (f *Font) renderRow(text string) []string {
+	out := make([][]rune, f.Height)
+	prevWidth := 0
+
+	for _, r := range text {
+		glyph, ok := f.glyphs[r]
+		if !ok {
+			continue
+		}
+		width := glyphWidth(glyph)
+		amount := f.smushAmount(out, glyph, prevWidth, width)
+
+		for row := range out {
+			line := out[row]
+			ch := glyph[row]
+			for k := 0; k < amount && k < len(ch); k++ {
+				if i := len(line) - amount + k; i >= 0 {
+					line[i] = f.smush(line[i], ch[k], prevWidth, width)
+				}
+			}
+			if amount < len(ch) {
+				line = append(line, ch[amount:]...)
+			}
+			out[row] = line
+		}
+		prevWidth = width
+	}
+
+	lines := make([]string, len(out))
+	for i, line := range out {
+		lines[i] = strings.TrimRight(strings.ReplaceAll(string(line), string(f.hardblank), " "), " ")
+	}
+	return lines
+}
+
+// smushAmount returns how many columns the glyph can move left into the
+// output: as far as every row allows without overlapping characters,
+// plus one where the overlapping characters smush
+func (f *Font) smushAmount(out [][]rune, glyph [][]rune, prevWidth, width int) int {
+	if f.layout&(layoutKern|layoutSmush) == 0 {
+		return 0
+	}
+
+	amount := width
+	for row := range out {
+		line, ch := out[row], glyph[row]
+
+		// Last visible character of the output and its position
+		end := len(line) - 1
+		for end >= 0 && line[end] == ' ' {
+			end--
+		}
+		// Leading spaces of the glyph
+		start := 0
+		for start < len(ch) && ch[start] == ' ' {
+			start++
+		}
+
+		n := start + len(line) - 1 - end
+		if end < 0 {
+			n = start + len(line)
+		} else if start < len(ch) && f.smush(line[end], ch[start], prevWidth, width) != 0 {
+			n++
+		}
+		amount = min(amount, n)
+	}
+	return max(amount, 0)
+}
+
+// smush returns the character that results from overlapping left and
+// right, or 0 if they cannot overlap
+func (f *Font) smush(left, right rune, prevWidth, width int) rune {
+	if left == ' ' {
+		return right
+	}
+	if right == ' ' {
+		return left
+	}
+	if prevWidth < 2 || width < 2 {
+		return 0
+	}
+	if f.layout&layoutSmush == 0 {
+		return 0
+	}
+
+	// Universal smushing: the right character wins, but hardblanks lose
+	if f.layout&63 == 0 {
+		if right == f.hardblank {
+			return left
+		}
+		return right
+	}
+
+	hb := f.hardblank
+	if f.layout&smushHardblank != 0 && left == hb && right == hb {
+		return left
+	}
+	if left == hb || right == hb {
+		return 0
+	}
+	if f.layout&smushEqual != 0 && left == right {
+		return left
+	}
+	if f.layout&smushLowline != 0 {
+		if left == '_' && strings.ContainsRune(`|/\[]{}()<>`, right) {
+			return right
+		}
+		if right == '_' && strings.ContainsRune(`|/\[]{}()<>`, left) {
+			return left
+		}
+	}
+	if f.layout&smushHierarchy != 0 {
+		classes := []string{"|", `/\`, "[]", "{}", "()", "<>"}
+		for i, class := range classes {
+			higher := strings.Join(classes[i+1:], "")
+			if strings.ContainsRune(class, left) && strings.ContainsRune(higher, right) {
+				return right
+			}
+			if strings.ContainsRune(class, right) && strings.ContainsRune(higher, left) {
+				return left
+			}
+		}
+	}
+	if f.layout&smushPair != 0 {
+		switch string([]rune{left, right}) {
+		case "[]", "][", "{}", "}{", "()", ")(":
+			return '|'
+		}
+	}
+	if f.layout&smushBigX != 0 {
+		switch string([]rune{left, right}) {
+		case `/\`:
+			return '|'
+		case `\/`:
+			return 'Y'
+		case "><":
+			return 'X'
+		}
+	}
+	return 0
+}
+
+// glyphWidth returns the width of a glyph's widest line
+func glyphWidth(glyph [][]rune) int {
+	width := 0
+	for _, line := range glyph {
+		width = max(width, len(line))
+	}
+	return width
+}
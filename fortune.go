@@ -0,0 +1,139 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/vnykmshr/gowsay/cow"
+	"github.com/vnykmshr/gowsay/fortune"
+	"github.com/vnykmshr/gowsay/locale"
+)
+
+// runFortune implements the "fortune" subcommand: a random fortune said by a
+// cow, like `fortune | cowsay`
+func runFortune(args []string, w io.Writer) error {
+	fs := flag.NewFlagSet("fortune", flag.ContinueOnError)
+	var (
+		short     = fs.Bool("s", false, "Short fortunes only")
+		long      = fs.Bool("l", false, "Long fortunes only")
+		length    = fs.Int("n", fortune.DefaultShortLength, "Longest fortune, in characters, that counts as short")
+		offensive = fs.Bool("o", false, "Offensive fortunes only")
+		all       = fs.Bool("a", false, "Offensive and inoffensive fortunes")
+		path      = fs.String("f", os.Getenv("GOWSAY_FORTUNE_PATH"), "Fortune files or directories (colon-separated)")
+		listPacks = fs.Bool("packs", false, "List fortune packs")
+		cowName   = fs.String("c", "default", "Cow name to use, or random[:tag]")
+		mood      = fs.String("m", "", "Mood, or random")
+		random    = fs.Bool("r", false, "Random cow and mood")
+		think     = fs.Bool("t", false, "Think instead of say")
+		columns   = fs.Int("w", 40, "Column width for text wrapping")
+		safe      = fs.Bool("safe", envBool("GOWSAY_SAFE_MODE"), "Safe mode: no offensive fortunes, and hide cows and moods not rated safe")
+		seed      = fs.Int64("seed", 0, "Seed for random choices, to reproduce a previous output")
+		printSeed = fs.Bool("print-seed", false, "Print the seed to stderr, to reproduce the fortune with -seed")
+	)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage:\n  gowsay fortune [options] [pack...]\n\nOptions:\n")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	p := locale.NewPrinter(locale.FromEnv())
+	if *safe && (*offensive || *all) {
+		return errors.New(p.Sprintf("offensive fortunes are not available in safe mode"))
+	}
+
+	packs, err := fortunePacks(*path, p)
+	if err != nil {
+		return err
+	}
+
+	if *listPacks {
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		for _, pack := range packs {
+			if *safe && pack.Offensive {
+				continue
+			}
+			kind := ""
+			if pack.Offensive {
+				kind = "offensive"
+			}
+			fmt.Fprintf(tw, "  %s\t%d\t%s\n", pack.Name, len(pack.Fortunes), kind)
+		}
+		return tw.Flush()
+	}
+
+	fortunes, err := fortune.Choose(packs, fortune.Options{
+		Packs:       fs.Args(),
+		Short:       *short,
+		Long:        *long,
+		ShortLength: *length,
+		Offensive:   *offensive,
+		All:         *all,
+	})
+	if err != nil {
+		return err
+	}
+
+	seeded := false
+	fs.Visit(func(f *flag.Flag) { seeded = seeded || f.Name == "seed" })
+	if !seeded {
+		*seed = cow.NewSeed()
+	}
+	rng := cow.NewRand(*seed)
+
+	weights, err := cow.ParseWeights(os.Getenv("GOWSAY_WEIGHTS"))
+	if err != nil {
+		return err
+	}
+	sel := cow.Selector{
+		SafeMode: *safe,
+		Rand:     rng,
+		Weights:  weights,
+		Exclude:  cow.ParseList(os.Getenv("GOWSAY_EXCLUDE")),
+		Messages: fortunes,
+	}
+
+	name, moodName, err := chooseCow(sel, p, *cowName, *mood, *random)
+	if err != nil {
+		return err
+	}
+
+	action := cow.ActionSay
+	if *think {
+		action = cow.ActionThink
+	}
+
+	renderer := &cow.Renderer{Rand: rng}
+	if _, err := fmt.Fprint(w, renderer.Render([]string{sel.RandomMessage()}, name, moodName, action, *columns)); err != nil {
+		return err
+	}
+
+	// Fortunes often run from shell profiles, so the seed is only reported
+	// when asked for
+	if *printSeed {
+		fmt.Fprintf(os.Stderr, "seed: %d\n", rng.Seed())
+	}
+	return nil
+}
+
+// fortunePacks loads the fortune packs on path, or the built-in moo
+// messages in the printer's language as a single "moo" pack if path is empty
+func fortunePacks(path string, p *locale.Printer) ([]*fortune.Pack, error) {
+	if path == "" {
+		moos := p.Messages()
+		if len(moos) == 0 {
+			moos = cow.Messages()
+		}
+		return []*fortune.Pack{{Name: "moo", Fortunes: moos}}, nil
+	}
+	packs, err := fortune.LoadPath(path)
+	if err != nil {
+		return nil, fmt.Errorf("loading fortunes: %w", err)
+	}
+	return packs, nil
+}
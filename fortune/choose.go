@@ -0,0 +1,64 @@
+package fortune
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"unicode/utf8"
+)
+
+// DefaultShortLength is the longest a fortune can be and still count as short
+const DefaultShortLength = 160
+
+// Options narrows down the fortunes to pick from, like the options of the
+// classic fortune program
+type Options struct {
+	Packs       []string // pack names to use, or all packs if empty
+	Short       bool     // only fortunes up to ShortLength characters
+	Long        bool     // only fortunes longer than ShortLength characters
+	ShortLength int      // defaults to DefaultShortLength
+	Offensive   bool     // only offensive packs
+	All         bool     // offensive and inoffensive packs
+}
+
+// ErrNoFortunes is returned when no fortune matches the options
+var ErrNoFortunes = errors.New("no fortunes match")
+
+// Choose returns the fortunes from packs that match the options
+func Choose(packs []*Pack, opts Options) ([]string, error) {
+	if opts.Short && opts.Long {
+		return nil, errors.New("short and long fortunes are mutually exclusive")
+	}
+	shortLength := opts.ShortLength
+	if shortLength <= 0 {
+		shortLength = DefaultShortLength
+	}
+
+	for _, name := range opts.Packs {
+		if !slices.ContainsFunc(packs, func(p *Pack) bool { return p.Name == name }) {
+			return nil, fmt.Errorf("fortune pack '%s' not found", name)
+		}
+	}
+
+	var fortunes []string
+	for _, pack := range packs {
+		if len(opts.Packs) > 0 && !slices.Contains(opts.Packs, pack.Name) {
+			continue
+		}
+		if !opts.All && pack.Offensive != opts.Offensive {
+			continue
+		}
+		for _, f := range pack.Fortunes {
+			short := utf8.RuneCountInString(f) <= shortLength
+			if (opts.Short && !short) || (opts.Long && short) {
+				continue
+			}
+			fortunes = append(fortunes, f)
+		}
+	}
+
+	if len(fortunes) == 0 {
+		return nil, ErrNoFortunes
+	}
+	return fortunes, nil
+}
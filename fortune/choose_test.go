@@ -0,0 +1,60 @@
+package fortune
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestChoose(t *testing.T) {
+	packs := []*Pack{
+		{Name: "farm", Fortunes: []string{"Moo.", "The grass is always greener on the udder side."}},
+		{Name: "barn", Fortunes: []string{"Hay!"}},
+		{Name: "barnyard", Offensive: true, Fortunes: []string{"Rude moo."}},
+	}
+
+	tests := []struct {
+		name string
+		opts Options
+		want int
+	}{
+		{"all inoffensive", Options{}, 3},
+		{"pack by name", Options{Packs: []string{"barn"}}, 1},
+		{"short", Options{Short: true, ShortLength: 10}, 2},
+		{"long", Options{Long: true, ShortLength: 10}, 1},
+		{"offensive only", Options{Offensive: true}, 1},
+		{"offensive and inoffensive", Options{All: true}, 4},
+		{"offensive pack by name needs opt-in", Options{Packs: []string{"barnyard"}, All: true}, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Choose(packs, tt.opts)
+			if err != nil {
+				t.Fatalf("Choose() error = %v", err)
+			}
+			if len(got) != tt.want {
+				t.Errorf("Choose() = %d fortunes %q, want %d", len(got), got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChoose_Errors(t *testing.T) {
+	packs := []*Pack{
+		{Name: "farm", Fortunes: []string{"Moo."}},
+		{Name: "barnyard", Offensive: true, Fortunes: []string{"Rude moo."}},
+	}
+
+	if _, err := Choose(packs, Options{Short: true, Long: true}); err == nil {
+		t.Error("Choose() with short and long should fail")
+	}
+	if _, err := Choose(packs, Options{Packs: []string{"nope"}}); err == nil {
+		t.Error("Choose() with an unknown pack should fail")
+	}
+	if _, err := Choose(packs, Options{Long: true}); !errors.Is(err, ErrNoFortunes) {
+		t.Errorf("Choose() with no long fortunes error = %v, want ErrNoFortunes", err)
+	}
+	if _, err := Choose(packs, Options{Packs: []string{"barnyard"}}); !errors.Is(err, ErrNoFortunes) {
+		t.Errorf("Choose() of an offensive pack without opt-in error = %v, want ErrNoFortunes", err)
+	}
+}
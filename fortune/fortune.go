@@ -0,0 +1,167 @@
+package fortune
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// offensiveDir is the subdirectory that holds offensive packs, by fortune convention
+const offensiveDir = "off"
+
+// Pack is a collection of fortunes loaded from one fortune file. Offensive
+// packs are only used when asked for explicitly.
+type Pack struct {
+	Name      string
+	Path      string
+	Offensive bool
+	Fortunes  []string
+}
+
+// Load reads the fortune file at path. If a strfile index exists next to it
+// (path + ".dat"), the fortunes are located through the index; otherwise the
+// file is split on "%" lines.
+func Load(path string) (*Pack, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var fortunes []string
+	if dat, err := os.Open(path + ".dat"); err == nil {
+		defer dat.Close()
+		idx, err := ReadIndex(dat)
+		if err != nil {
+			return nil, fmt.Errorf("%s.dat: %w", path, err)
+		}
+		if fortunes, err = idx.Split(data); err != nil {
+			return nil, fmt.Errorf("%s.dat: %w", path, err)
+		}
+	} else if errors.Is(err, os.ErrNotExist) {
+		if fortunes, err = Parse(strings.NewReader(string(data))); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	} else {
+		return nil, err
+	}
+
+	name := filepath.Base(path)
+	offensive := strings.HasSuffix(name, "-o") || filepath.Base(filepath.Dir(path)) == offensiveDir
+	return &Pack{Name: name, Path: path, Offensive: offensive, Fortunes: fortunes}, nil
+}
+
+// LoadDir loads every fortune file in dir, skipping strfile indexes,
+// ".u8" links and hidden files. Packs in an "off" subdirectory are loaded
+// as offensive.
+func LoadDir(dir string) ([]*Pack, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var packs []*Pack
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() && name == offensiveDir {
+			offensive, err := LoadDir(filepath.Join(dir, name))
+			if err != nil {
+				return nil, err
+			}
+			packs = append(packs, offensive...)
+			continue
+		}
+		if entry.IsDir() || strings.HasPrefix(name, ".") || !isFortuneFile(name) {
+			continue
+		}
+		pack, err := Load(filepath.Join(dir, name))
+		if err != nil {
+			return nil, err
+		}
+		packs = append(packs, pack)
+	}
+	return packs, nil
+}
+
+// LoadPath loads fortune packs from a list of files and directories
+// separated by the OS path list separator (":" on Unix), like $PATH
+func LoadPath(list string) ([]*Pack, error) {
+	var packs []*Pack
+	for _, path := range filepath.SplitList(list) {
+		if path == "" {
+			continue
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if info.IsDir() {
+			dirPacks, err := LoadDir(path)
+			if err != nil {
+				return nil, err
+			}
+			packs = append(packs, dirPacks...)
+			continue
+		}
+		pack, err := Load(path)
+		if err != nil {
+			return nil, err
+		}
+		packs = append(packs, pack)
+	}
+	return packs, nil
+}
+
+// Messages returns the fortunes of all inoffensive packs as one list
+func Messages(packs []*Pack) []string {
+	var messages []string
+	for _, pack := range packs {
+		if !pack.Offensive {
+			messages = append(messages, pack.Fortunes...)
+		}
+	}
+	return messages
+}
+
+// Parse reads "%"-separated fortunes. Line breaks inside a fortune are
+// kept; empty fortunes are dropped.
+func Parse(r io.Reader) ([]string, error) {
+	var fortunes []string
+	var lines []string
+
+	flush := func() {
+		if text := strings.TrimRight(strings.Join(lines, "\n"), "\n"); strings.TrimSpace(text) != "" {
+			fortunes = append(fortunes, text)
+		}
+		lines = lines[:0]
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+		if line == "%" {
+			flush()
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	flush()
+
+	return fortunes, nil
+}
+
+// isFortuneFile reports whether a file in a fortune directory holds fortunes
+func isFortuneFile(name string) bool {
+	switch filepath.Ext(name) {
+	case ".dat", ".u8", ".sh", ".md":
+		return false
+	}
+	return true
+}
@@ -0,0 +1,172 @@
+package fortune
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	input := "Holy cow!\n%\nThe grass is always greener\n  on the udder side.\n%\n%\n\n%\nMoo."
+	want := []string{
+		"Holy cow!",
+		"The grass is always greener\n  on the udder side.",
+		"Moo.",
+	}
+
+	got, err := Parse(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Parse() = %q, want %q", got, want)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	pack, err := Load("testdata/packs/farm")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if pack.Name != "farm" {
+		t.Errorf("Name = %s, want farm", pack.Name)
+	}
+	if len(pack.Fortunes) != 3 {
+		t.Fatalf("got %d fortunes, want 3: %q", len(pack.Fortunes), pack.Fortunes)
+	}
+	if want := "The grass is always greener\n  on the udder side.\n\t\t-- Anonymous"; pack.Fortunes[1] != want {
+		t.Errorf("multi-line fortune = %q, want %q", pack.Fortunes[1], want)
+	}
+}
+
+func TestLoad_CRLF(t *testing.T) {
+	pack, err := Load("testdata/packs/crlf")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(pack.Fortunes) != 2 || pack.Fortunes[1] != "Crlf cow" {
+		t.Errorf("Fortunes = %q", pack.Fortunes)
+	}
+}
+
+func TestLoad_Missing(t *testing.T) {
+	if _, err := Load("testdata/packs/nope"); err == nil {
+		t.Error("Load() of a missing file should fail")
+	}
+}
+
+func TestLoadDir(t *testing.T) {
+	packs, err := LoadDir("testdata/packs")
+	if err != nil {
+		t.Fatalf("LoadDir() error = %v", err)
+	}
+	if len(packs) != 3 {
+		t.Fatalf("got %d packs, want 3", len(packs))
+	}
+	for _, pack := range packs {
+		if want := pack.Name == "barnyard"; pack.Offensive != want {
+			t.Errorf("pack %s Offensive = %v, want %v", pack.Name, pack.Offensive, want)
+		}
+	}
+	if got := len(Messages(packs)); got != 5 {
+		t.Errorf("Messages() has %d fortunes, want 5 (offensive packs left out)", got)
+	}
+}
+
+func TestLoadPath(t *testing.T) {
+	list := "testdata/packs/farm" + string(os.PathListSeparator) + "testdata/packs"
+	packs, err := LoadPath(list)
+	if err != nil {
+		t.Fatalf("LoadPath() error = %v", err)
+	}
+	if len(packs) != 4 {
+		t.Errorf("got %d packs, want 4", len(packs))
+	}
+
+	if _, err := LoadPath("testdata/nope"); err == nil {
+		t.Error("LoadPath() of a missing path should fail")
+	}
+}
+
+func TestLoad_Index(t *testing.T) {
+	text := "First\n%\nSecond\nwith two lines\n%\nThird\n%\n"
+	dir := t.TempDir()
+	path := filepath.Join(dir, "indexed")
+	writeFile(t, path, text)
+
+	// Index only the second and first fortunes, shuffled
+	writeIndex(t, path+".dat", FlagRandom, []uint32{8, 0, uint32(len(text))})
+
+	pack, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	want := []string{"Second\nwith two lines", "First"}
+	if !reflect.DeepEqual(pack.Fortunes, want) {
+		t.Errorf("Fortunes = %q, want %q", pack.Fortunes, want)
+	}
+}
+
+func TestLoad_RotatedIndex(t *testing.T) {
+	text := "Ubyl pbj!\n%\n"
+	dir := t.TempDir()
+	path := filepath.Join(dir, "rotated")
+	writeFile(t, path, text)
+	writeIndex(t, path+".dat", FlagRotated, []uint32{0, uint32(len(text))})
+
+	pack, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(pack.Fortunes) != 1 || pack.Fortunes[0] != "Holy cow!" {
+		t.Errorf("Fortunes = %q, want [Holy cow!]", pack.Fortunes)
+	}
+}
+
+func TestLoad_BadIndex(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "bad")
+	writeFile(t, path, "Moo\n%\n")
+	writeFile(t, path+".dat", "not an index")
+
+	if _, err := Load(path); err == nil {
+		t.Error("Load() with a corrupt index should fail")
+	}
+
+	writeIndex(t, path+".dat", 0, []uint32{100, 104})
+	if _, err := Load(path); err == nil {
+		t.Error("Load() with offsets past the end should fail")
+	}
+}
+
+func writeFile(t *testing.T, path, data string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// writeIndex writes a strfile index for the given offsets, the last of which
+// marks the end of the file
+func writeIndex(t *testing.T, path string, flags uint32, offsets []uint32) {
+	t.Helper()
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	header := []uint32{2, uint32(len(offsets) - 1), 0, 0, flags}
+	if err := binary.Write(f, binary.BigEndian, header); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Write([]byte{'%', 0, 0, 0}); err != nil {
+		t.Fatal(err)
+	}
+	if err := binary.Write(f, binary.BigEndian, offsets); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,121 @@
+package fortune
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// strfile header flags
+const (
+	FlagRandom   = 0x1 // offsets have been shuffled
+	FlagOrdered  = 0x2 // offsets have been sorted
+	FlagRotated  = 0x4 // fortunes are ROT13 encoded
+	FlagComments = 0x8 // lines starting with the delimiter twice are comments
+)
+
+// maxFortunes bounds the fortune count read from an index, to reject corrupt files
+const maxFortunes = 1 << 20
+
+// Index is a strfile ".dat" index: a big-endian header followed by the
+// byte offset of each fortune in the text file
+type Index struct {
+	Version  uint32
+	Count    uint32
+	Longest  uint32
+	Shortest uint32
+	Flags    uint32
+	Delim    byte
+	Offsets  []uint32
+}
+
+// ReadIndex decodes a strfile index
+func ReadIndex(r io.Reader) (*Index, error) {
+	var header struct {
+		Version, Count, Longest, Shortest, Flags uint32
+		Delim                                    [4]byte
+	}
+	if err := binary.Read(r, binary.BigEndian, &header); err != nil {
+		return nil, fmt.Errorf("reading strfile header: %w", err)
+	}
+	if header.Version < 1 || header.Version > 2 {
+		return nil, fmt.Errorf("unsupported strfile version %d", header.Version)
+	}
+	if header.Count > maxFortunes {
+		return nil, fmt.Errorf("strfile index claims %d fortunes", header.Count)
+	}
+
+	// strfile writes one offset per fortune plus one for the end of the file
+	offsets := make([]uint32, header.Count+1)
+	if err := binary.Read(r, binary.BigEndian, offsets); err != nil {
+		return nil, fmt.Errorf("reading strfile offsets: %w", err)
+	}
+
+	return &Index{
+		Version:  header.Version,
+		Count:    header.Count,
+		Longest:  header.Longest,
+		Shortest: header.Shortest,
+		Flags:    header.Flags,
+		Delim:    header.Delim[0],
+		Offsets:  offsets,
+	}, nil
+}
+
+// Split cuts the fortune file text into fortunes using the index offsets
+func (idx *Index) Split(data []byte) ([]string, error) {
+	delim := string(idx.Delim)
+	fortunes := make([]string, 0, idx.Count)
+
+	for i := 0; i < int(idx.Count); i++ {
+		start := int(idx.Offsets[i])
+		if start > len(data) {
+			return nil, errors.New("strfile offset beyond end of file")
+		}
+
+		// A fortune runs until the next delimiter line
+		var lines []string
+		for rest := data[start:]; len(rest) > 0; {
+			line := rest
+			if n := bytes.IndexByte(rest, '\n'); n >= 0 {
+				line, rest = rest[:n], rest[n+1:]
+			} else {
+				rest = nil
+			}
+
+			trimmed := strings.TrimSuffix(string(line), "\r")
+			if trimmed == delim {
+				break
+			}
+			if idx.Flags&FlagComments != 0 && strings.HasPrefix(trimmed, delim+delim) {
+				continue
+			}
+			lines = append(lines, trimmed)
+		}
+
+		text := strings.TrimRight(strings.Join(lines, "\n"), "\n")
+		if idx.Flags&FlagRotated != 0 {
+			text = rot13(text)
+		}
+		if strings.TrimSpace(text) != "" {
+			fortunes = append(fortunes, text)
+		}
+	}
+	return fortunes, nil
+}
+
+// rot13 decodes the rotated text of offensive fortune files
+func rot13(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return 'a' + (r-'a'+13)%26
+		case r >= 'A' && r <= 'Z':
+			return 'A' + (r-'A'+13)%26
+		}
+		return r
+	}, s)
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestRunFortune(t *testing.T) {
+	t.Setenv("GOWSAY_FORTUNE_PATH", "fortune/testdata/packs")
+
+	var a, b bytes.Buffer
+	args := []string{"-seed", "7", "-c", "random"}
+	if err := runFortune(args, &a); err != nil {
+		t.Fatalf("runFortune() error = %v", err)
+	}
+	if err := runFortune(args, &b); err != nil {
+		t.Fatalf("runFortune() error = %v", err)
+	}
+	if a.String() != b.String() {
+		t.Error("same seed should render the same fortune")
+	}
+
+	var short bytes.Buffer
+	if err := runFortune([]string{"-s", "-n", "10", "-seed", "1", "farm"}, &short); err != nil {
+		t.Fatalf("runFortune(-s) error = %v", err)
+	}
+	if !strings.Contains(short.String(), "Holy cow!") && !strings.Contains(short.String(), "Moo.") {
+		t.Errorf("runFortune(-s farm) should say a short farm fortune, got:\n%s", short.String())
+	}
+
+	var off bytes.Buffer
+	if err := runFortune([]string{"-o", "-seed", "1"}, &off); err != nil {
+		t.Fatalf("runFortune(-o) error = %v", err)
+	}
+	if !strings.Contains(off.String(), "farmer") {
+		t.Errorf("runFortune(-o) should say the offensive fortune, got:\n%s", off.String())
+	}
+
+	var packs bytes.Buffer
+	if err := runFortune([]string{"-packs"}, &packs); err != nil {
+		t.Fatalf("runFortune(-packs) error = %v", err)
+	}
+	if !strings.Contains(packs.String(), "farm") || !strings.Contains(packs.String(), "offensive") {
+		t.Errorf("runFortune(-packs) = %q, want farm and an offensive pack", packs.String())
+	}
+}
+
+func TestRunFortune_PrintSeed(t *testing.T) {
+	t.Setenv("GOWSAY_FORTUNE_PATH", "")
+
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"quiet by default", nil, ""},
+		{"asked for", []string{"-print-seed", "-seed", "7"}, "seed: 7\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, w, err := os.Pipe()
+			if err != nil {
+				t.Fatal(err)
+			}
+			stderr := os.Stderr
+			os.Stderr = w
+			err = runFortune(tt.args, io.Discard)
+			os.Stderr = stderr
+			w.Close()
+			if err != nil {
+				t.Fatalf("runFortune() error = %v", err)
+			}
+			got, _ := io.ReadAll(r)
+			if string(got) != tt.want {
+				t.Errorf("stderr = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunFortune_BuiltIn(t *testing.T) {
+	t.Setenv("GOWSAY_FORTUNE_PATH", "")
+
+	var out bytes.Buffer
+	if err := runFortune([]string{"-seed", "1"}, &out); err != nil {
+		t.Fatalf("runFortune() error = %v", err)
+	}
+	if !strings.Contains(out.String(), "\\") {
+		t.Error("output should contain a cow")
+	}
+}
+
+func TestRunFortune_Errors(t *testing.T) {
+	t.Setenv("GOWSAY_FORTUNE_PATH", "fortune/testdata/packs")
+
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"short and long", []string{"-s", "-l"}},
+		{"unknown pack", []string{"nope"}},
+		{"offensive in safe mode", []string{"-safe", "-o"}},
+		{"unknown cow", []string{"-c", "nope"}},
+		{"bad path", []string{"-f", "nope"}},
+		{"unknown flag", []string{"-nope"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			if err := runFortune(tt.args, &out); err == nil {
+				t.Errorf("runFortune(%v) should fail", tt.args)
+			}
+		})
+	}
+}
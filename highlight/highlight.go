@@ -0,0 +1,111 @@
+package highlight
+
+import (
+	"sort"
+	"strings"
+)
+
+// Kind is the kind of a token
+type Kind int
+
+// Token kinds
+const (
+	Plain Kind = iota
+	Keyword
+	Type
+	String
+	Number
+	Literal
+	Comment
+	Key
+	Variable
+)
+
+var kindNames = [...]string{"plain", "keyword", "type", "string", "number", "literal", "comment", "key", "variable"}
+
+// String returns the kind's name, such as "keyword"
+func (k Kind) String() string {
+	return kindNames[k]
+}
+
+// Token is a run of code of one kind
+type Token struct {
+	Text string
+	Kind Kind
+}
+
+// lexer splits code into tokens
+type lexer func(code string) []Token
+
+// lexers maps language names and aliases to their lexers
+var lexers = map[string]lexer{
+	"go":     lexGo,
+	"golang": lexGo,
+	"sh":     lexShell,
+	"bash":   lexShell,
+	"shell":  lexShell,
+	"zsh":    lexShell,
+	"json":   lexJSON,
+	"yaml":   lexYAML,
+	"yml":    lexYAML,
+}
+
+// Supported reports whether a language, or one of its aliases, can be
+// highlighted. Names are case-insensitive.
+func Supported(lang string) bool {
+	_, ok := lexers[strings.ToLower(lang)]
+	return ok
+}
+
+// Languages returns the supported language names and aliases, sorted
+func Languages() []string {
+	names := make([]string, 0, len(lexers))
+	for name := range lexers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Highlight splits code into tokens. Code in an unsupported language is a
+// single plain token. Concatenating the tokens' text gives back the code.
+func Highlight(lang, code string) []Token {
+	lex, ok := lexers[strings.ToLower(lang)]
+	if !ok {
+		return []Token{{Text: code}}
+	}
+	return merge(lex(code))
+}
+
+// Lines splits tokens at newlines, so each line of code is a list of
+// tokens. Tokens that span lines, like block comments, are split.
+func Lines(tokens []Token) [][]Token {
+	lines := [][]Token{nil}
+	for _, tok := range tokens {
+		for i, part := range strings.Split(tok.Text, "\n") {
+			if i > 0 {
+				lines = append(lines, nil)
+			}
+			if part != "" {
+				lines[len(lines)-1] = append(lines[len(lines)-1], Token{Text: part, Kind: tok.Kind})
+			}
+		}
+	}
+	return lines
+}
+
+// merge joins neighbouring tokens of the same kind and drops empty ones
+func merge(tokens []Token) []Token {
+	var out []Token
+	for _, tok := range tokens {
+		if tok.Text == "" {
+			continue
+		}
+		if n := len(out); n > 0 && out[n-1].Kind == tok.Kind {
+			out[n-1].Text += tok.Text
+			continue
+		}
+		out = append(out, tok)
+	}
+	return out
+}
@@ -0,0 +1,91 @@
+package highlight
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+// marked writes tokens with their kinds marked, e.g. "{keyword:func} main"
+func marked(tokens []Token) string {
+	var sb strings.Builder
+	for _, tok := range tokens {
+		if tok.Kind == Plain {
+			sb.WriteString(tok.Text)
+			continue
+		}
+		sb.WriteString("{" + tok.Kind.String() + ":" + tok.Text + "}")
+	}
+	return sb.String()
+}
+
+func TestHighlight(t *testing.T) {
+	tests := []struct {
+		lang string
+		code string
+		want string
+	}{
+		{"go", `func main() { fmt.Println("moo", 42) }`,
+			`{keyword:func} main() { fmt.Println({string:"moo"}, {number:42}) }`},
+		{"Go", "var n int = 0x1F // count\n/* multi\nline */ x := `raw\n` + 'c'",
+			"{keyword:var} n {type:int} = {number:0x1F} {comment:// count}\n{comment:/* multi\nline */} x := {string:`raw\n`} + {string:'c'}"},
+		{"golang", `if err != nil { return 1.5e-3 }`,
+			`{keyword:if} err != {literal:nil} { {keyword:return} {number:1.5e-3} }`},
+		{"go", `s := "esc\"aped" + héllo`,
+			`s := {string:"esc\"aped"} + héllo`},
+		{"bash", `for f in *.go; do echo "$f" $HOME ${X:-y}; done # loop`,
+			`{keyword:for} f {keyword:in} *.go; {keyword:do} echo {string:"$f"} {variable:$HOME} {variable:${X:-y}}; {keyword:done} {comment:# loop}`},
+		{"sh", "NAME=moo echo a#b $1 'it''s'\nexport done=1",
+			"{variable:NAME}=moo echo a#b {variable:$1} {string:'it''s'}\n{keyword:export} {variable:done}=1"},
+		{"json", `{"cow": "moo", "legs": 4, "milk": -1.5e2, "ok": true, "x": null}`,
+			`{{key:"cow"}: {string:"moo"}, {key:"legs"}: {number:4}, {key:"milk"}: {number:-1.5e2}, {key:"ok"}: {literal:true}, {key:"x"}: {literal:null}}`},
+		{"yaml", "---\n# herd\ncows:\n  - name: Daisy # best\n    legs: 4\n    happy: yes\n    says: \"moo # not a comment\"\nurl: http://example.com",
+			"{keyword:---}\n{comment:# herd}\n{key:cows}:\n  - {key:name}: Daisy {comment:# best}\n    {key:legs}: {number:4}\n    {key:happy}: {literal:yes}\n    {key:says}: {string:\"moo # not a comment\"}\n{key:url}: http://example.com"},
+		{"yml", "- 1.5\n- plain text", "- {number:1.5}\n- plain text"},
+		{"cobol", `DISPLAY "MOO"`, `DISPLAY "MOO"`},
+		{"", "func", "func"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.lang, func(t *testing.T) {
+			tokens := Highlight(tt.lang, tt.code)
+			if got := marked(tokens); got != tt.want {
+				t.Errorf("Highlight(%s) =\n%s\nwant\n%s", tt.lang, got, tt.want)
+			}
+
+			var text strings.Builder
+			for _, tok := range tokens {
+				text.WriteString(tok.Text)
+			}
+			if text.String() != tt.code {
+				t.Errorf("Highlight(%s) tokens join to %q, want the code back", tt.lang, text.String())
+			}
+		})
+	}
+}
+
+func TestLines(t *testing.T) {
+	lines := Lines(Highlight("go", "x := 1 /* a\nb */\n\ny"))
+	got := make([]string, len(lines))
+	for i, line := range lines {
+		got[i] = marked(line)
+	}
+	want := []string{"x := {number:1} {comment:/* a}", "{comment:b */}", "", "y"}
+	if !slices.Equal(got, want) {
+		t.Errorf("Lines() = %q, want %q", got, want)
+	}
+}
+
+func TestSupported(t *testing.T) {
+	for _, lang := range []string{"go", "GO", "bash", "json", "yaml", "yml"} {
+		if !Supported(lang) {
+			t.Errorf("Supported(%s) = false", lang)
+		}
+	}
+	if Supported("cobol") || Supported("") {
+		t.Error("only the built-in languages should be supported")
+	}
+	if langs := Languages(); !slices.IsSorted(langs) || !slices.Contains(langs, "shell") {
+		t.Errorf("Languages() = %v", langs)
+	}
+}
@@ -0,0 +1,260 @@
+package highlight
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	goKeywords = words("break case chan const continue default defer else fallthrough for func go goto if import interface map package range return select struct switch type var")
+	goTypes    = words("any bool byte comparable complex64 complex128 error float32 float64 int int8 int16 int32 int64 rune string uint uint8 uint16 uint32 uint64 uintptr")
+	goLiterals = words("true false nil iota")
+
+	shellKeywords = words("if then else elif fi for while until do done case esac in function select return exit export local readonly declare unset shift break continue source")
+
+	jsonLiterals = words("true false null")
+	yamlLiterals = words("true false null yes no on off True False Null Yes No TRUE FALSE NULL ~")
+)
+
+// words builds a set from a space-separated list
+func words(s string) map[string]bool {
+	set := make(map[string]bool)
+	for _, w := range strings.Fields(s) {
+		set[w] = true
+	}
+	return set
+}
+
+// lexGo splits Go code into tokens
+func lexGo(code string) []Token {
+	var tokens []Token
+	for i := 0; i < len(code); {
+		c := code[i]
+		end, kind := i+1, Plain
+		switch {
+		case strings.HasPrefix(code[i:], "//"):
+			end, kind = lineEnd(code, i), Comment
+		case strings.HasPrefix(code[i:], "/*"):
+			end, kind = until(code, i+2, "*/"), Comment
+		case c == '"' || c == '\'':
+			end, kind = quoted(code, i, c, true), String
+		case c == '`':
+			end, kind = until(code, i+1, "`"), String
+		case isDigit(c):
+			end, kind = scanNumber(code, i), Number
+		case isIdentByte(c):
+			end = scan(code, i, isIdentByte)
+			switch word := code[i:end]; {
+			case goKeywords[word]:
+				kind = Keyword
+			case goTypes[word]:
+				kind = Type
+			case goLiterals[word]:
+				kind = Literal
+			}
+		}
+		tokens = append(tokens, Token{Text: code[i:end], Kind: kind})
+		i = end
+	}
+	return tokens
+}
+
+// lexShell splits shell scripts into tokens
+func lexShell(code string) []Token {
+	var tokens []Token
+	for i := 0; i < len(code); {
+		c := code[i]
+		end, kind := i+1, Plain
+		wordStart := i == 0 || strings.IndexByte(" \t\n;|&(", code[i-1]) >= 0
+		switch {
+		case c == '#' && wordStart:
+			end, kind = lineEnd(code, i), Comment
+		case c == '\'':
+			end, kind = until(code, i+1, "'"), String
+		case c == '"':
+			end, kind = quoted(code, i, c, false), String
+		case strings.HasPrefix(code[i:], "${"):
+			end, kind = until(code, i+2, "}"), Variable
+		case c == '$' && i+1 < len(code) && (isIdentByte(code[i+1]) || strings.IndexByte("@*#?$!-", code[i+1]) >= 0):
+			end, kind = i+2, Variable
+			if isIdentByte(code[i+1]) {
+				end = scan(code, i+1, isIdentByte)
+			}
+		case isIdentByte(c) && wordStart:
+			end = scan(code, i, isIdentByte)
+			next := byte(0)
+			if end < len(code) {
+				next = code[end]
+			}
+			switch word := code[i:end]; {
+			case next == '=':
+				kind = Variable
+			case shellKeywords[word] && (next == 0 || strings.IndexByte(" \t\n;", next) >= 0):
+				kind = Keyword
+			}
+		case isIdentByte(c):
+			end = scan(code, i, isIdentByte)
+		}
+		tokens = append(tokens, Token{Text: code[i:end], Kind: kind})
+		i = end
+	}
+	return tokens
+}
+
+// lexJSON splits JSON into tokens. Strings followed by a colon are keys.
+func lexJSON(code string) []Token {
+	var tokens []Token
+	for i := 0; i < len(code); {
+		c := code[i]
+		end, kind := i+1, Plain
+		switch {
+		case c == '"':
+			end, kind = quoted(code, i, c, true), String
+			if rest := strings.TrimLeft(code[end:], " \t\r\n"); strings.HasPrefix(rest, ":") {
+				kind = Key
+			}
+		case c == '-' || isDigit(c):
+			end, kind = scanNumber(code, i+1), Number
+		case isIdentByte(c):
+			end = scan(code, i, isIdentByte)
+			if jsonLiterals[code[i:end]] {
+				kind = Literal
+			}
+		}
+		tokens = append(tokens, Token{Text: code[i:end], Kind: kind})
+		i = end
+	}
+	return tokens
+}
+
+var (
+	// yamlKey matches the indent, an optional list item marker, and a key
+	yamlKey = regexp.MustCompile(`^(\s*(?:- +)*)("[^"]*"|'[^']*'|[^\s#'"{}\[\],:-][^:#]*?|-[^\s:#][^:#]*?)(\s*:)(?:\s|$)`)
+	// yamlNumber matches integers and floats
+	yamlNumber = regexp.MustCompile(`^[-+]?(?:\d[\d_]*(?:\.\d*)?|\.\d+)(?:[eE][-+]?\d+)?$|^0x[0-9a-fA-F]+$`)
+)
+
+// lexYAML splits YAML into tokens, a line at a time
+func lexYAML(code string) []Token {
+	var tokens []Token
+	for i, line := range strings.Split(code, "\n") {
+		if i > 0 {
+			tokens = append(tokens, Token{Text: "\n"})
+		}
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmed, "#"):
+			tokens = append(tokens, Token{Text: line, Kind: Comment})
+			continue
+		case trimmed == "---" || trimmed == "...":
+			tokens = append(tokens, Token{Text: line, Kind: Keyword})
+			continue
+		}
+
+		rest := line
+		if m := yamlKey.FindStringSubmatch(line); m != nil {
+			tokens = append(tokens, Token{Text: m[1]}, Token{Text: m[2], Kind: Key}, Token{Text: m[3]})
+			rest = line[len(m[1])+len(m[2])+len(m[3]):]
+		} else {
+			indent := len(line) - len(strings.TrimLeft(line, " -"))
+			tokens = append(tokens, Token{Text: line[:indent]})
+			rest = line[indent:]
+		}
+		tokens = append(tokens, yamlValue(rest)...)
+	}
+	return tokens
+}
+
+// yamlValue splits a YAML value and any trailing comment into tokens
+func yamlValue(s string) []Token {
+	var comment string
+	if v := strings.TrimLeft(s, " "); v != "" && v[0] != '"' && v[0] != '\'' {
+		if i := strings.Index(" "+s, " #"); i >= 0 {
+			s, comment = s[:i], s[i:]
+		}
+	}
+	value := strings.TrimSpace(s)
+	lead := s[:strings.Index(s, value)]
+	trail := s[len(lead)+len(value):]
+
+	kind := Plain
+	switch {
+	case value == "":
+	case value[0] == '"' || value[0] == '\'':
+		kind = String
+	case yamlNumber.MatchString(value):
+		kind = Number
+	case yamlLiterals[value]:
+		kind = Literal
+	}
+	return []Token{{Text: lead}, {Text: value, Kind: kind}, {Text: trail}, {Text: comment, Kind: Comment}}
+}
+
+// lineEnd returns the index of the end of the line containing i
+func lineEnd(code string, i int) int {
+	if j := strings.IndexByte(code[i:], '\n'); j >= 0 {
+		return i + j
+	}
+	return len(code)
+}
+
+// until returns the index just past the next delimiter at or after i, or
+// the end of the code if there is none
+func until(code string, i int, delim string) int {
+	if j := strings.Index(code[i:], delim); j >= 0 {
+		return i + j + len(delim)
+	}
+	return len(code)
+}
+
+// quoted returns the index just past the string quoted with q starting at
+// i, skipping backslash escapes. Single-line strings end at a newline.
+func quoted(code string, i int, q byte, singleLine bool) int {
+	for j := i + 1; j < len(code); j++ {
+		switch code[j] {
+		case '\\':
+			j++
+		case q:
+			return j + 1
+		case '\n':
+			if singleLine {
+				return j
+			}
+		}
+	}
+	return len(code)
+}
+
+// scan returns the index of the first byte at or after i not matching f
+func scan(code string, i int, f func(byte) bool) int {
+	for i < len(code) && f(code[i]) {
+		i++
+	}
+	return i
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// isIdentByte matches identifier bytes, including any non-ASCII ones so
+// multi-byte characters stay whole
+func isIdentByte(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || isDigit(c) || c >= 0x80
+}
+
+// scanNumber returns the index just past the number literal at i: digits,
+// letters for hex digits and suffixes, dots, underscores, and signs after
+// an exponent
+func scanNumber(code string, i int) int {
+	for ; i < len(code); i++ {
+		c := code[i]
+		switch {
+		case isIdentByte(c) && c < 0x80, c == '.':
+		case (c == '+' || c == '-') && i > 0 && (code[i-1] == 'e' || code[i-1] == 'E'):
+		default:
+			return i
+		}
+	}
+	return i
+}
@@ -14,6 +14,7 @@ import (
 	"time"
 
 	"github.com/vnykmshr/gowsay/api"
+	"github.com/vnykmshr/gowsay/cow"
 )
 
 // TestServerIntegration tests the full HTTP server with all endpoints
@@ -72,7 +73,7 @@ func TestServerIntegration(t *testing.T) {
 			t.Errorf("GET /api/cows: expected 200, got %d", resp.StatusCode)
 		}
 
-		var cowsResult map[string][]string
+		var cowsResult map[string][]cow.Info
 		if err := json.NewDecoder(resp.Body).Decode(&cowsResult); err != nil {
 			t.Fatalf("Failed to decode cows: %v", err)
 		}
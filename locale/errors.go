@@ -0,0 +1,70 @@
+package locale
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/vnykmshr/gowsay/canvas"
+	"github.com/vnykmshr/gowsay/cow"
+	"github.com/vnykmshr/gowsay/message"
+	"github.com/vnykmshr/gowsay/qr"
+)
+
+// Error returns the text of an error from the cow, canvas, message or qr
+// packages in the printer's language. Other errors are returned as they
+// are.
+func (p *Printer) Error(err error) string {
+	var (
+		unknownFilter    *cow.UnknownFilterError
+		unknownEffect    *cow.UnknownEffectError
+		unknownAccessory *cow.UnknownAccessoryError
+		conflict         *cow.AnchorConflictError
+		missing          *cow.MissingAnchorError
+		family           *canvas.FontFamilyError
+		outOfRange       *canvas.RangeError
+		badColor         *canvas.ColorError
+		badTemplate      *message.Error
+	)
+	switch {
+	case errors.As(err, &unknownFilter):
+		return p.Sprintf("filter '%s' not found", unknownFilter.Name)
+	case errors.As(err, &unknownEffect):
+		return p.Sprintf("animation '%s' not found", unknownEffect.Name)
+	case errors.As(err, &unknownAccessory):
+		return p.Sprintf("accessory '%s' not found", unknownAccessory.Name)
+	case errors.As(err, &conflict):
+		return p.Sprintf("accessories '%s' and '%s' both go on the %s anchor", conflict.First, conflict.Second, conflict.Anchor)
+	case errors.As(err, &missing):
+		return p.Sprintf("cow '%s' has no %s anchor for '%s'", missing.Cow, missing.Anchor, missing.Accessory)
+	case errors.As(err, &family):
+		return p.Sprintf("font family '%s' may only have letters, digits, spaces, commas, hyphens and quotes", family.Family)
+	case errors.As(err, &outOfRange):
+		switch outOfRange.Option {
+		case canvas.OptionFontSize:
+			return p.Sprintf("font size must be between %d and %d", outOfRange.Min, outOfRange.Max)
+		case canvas.OptionScale:
+			return p.Sprintf("scale must be between 1 and %d", outOfRange.Max)
+		case canvas.OptionPadding:
+			return p.Sprintf("padding must be between 0 and %d", outOfRange.Max)
+		}
+	case errors.As(err, &badColor):
+		return p.Sprintf("color '%s' must be #rgb, #rrggbb, #rrggbbaa or transparent", badColor.Color)
+	case errors.Is(err, canvas.ErrTooLarge):
+		return p.Sprintf("image must be at most %d pixels", canvas.MaxPixels)
+	case errors.Is(err, canvas.ErrAnimationTooLarge):
+		return p.Sprintf("animation must be at most %d pixels in all frames", canvas.MaxAnimationPixels)
+	case errors.Is(err, message.ErrTooLong):
+		return p.Sprintf("message template expands past %d bytes", message.MaxOutput)
+	case errors.As(err, &badTemplate):
+		// Template errors already say where in the message they are,
+		// after a "template: " prefix
+		return p.Sprintf("invalid message template: %s", strings.TrimPrefix(badTemplate.Error(), "template: "))
+	case errors.Is(err, qr.ErrLevel):
+		return p.Sprintf("QR level must be L, M, Q or H")
+	case errors.Is(err, qr.ErrQuietZone):
+		return p.Sprintf("QR quiet zone must be between 0 and %d", qr.MaxQuietZone)
+	case errors.Is(err, qr.ErrTooLong):
+		return p.Sprintf("text is too long for a QR code")
+	}
+	return err.Error()
+}
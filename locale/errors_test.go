@@ -0,0 +1,45 @@
+package locale
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/vnykmshr/gowsay/canvas"
+	"github.com/vnykmshr/gowsay/cow"
+	"github.com/vnykmshr/gowsay/message"
+	"github.com/vnykmshr/gowsay/qr"
+)
+
+func TestPrinter_Error(t *testing.T) {
+	_, accessoryErr := cow.ParseAccessories("cape")
+	_, conflictErr := cow.ParseAccessories("santa-hat,party-hat")
+	missingErr := cow.CheckAccessories("tux", []string{"sunglasses"})
+	_, templateErr := message.Template{}.Expand("{{nope}}")
+	opts := canvas.DefaultOptions()
+	opts.Scale = 0
+
+	tests := []struct {
+		name string
+		p    *Printer
+		err  error
+		want string
+	}{
+		{"accessory", nil, accessoryErr, "accessory 'cape' not found"},
+		{"anchor conflict", nil, conflictErr, "accessories 'santa-hat' and 'party-hat' both go on the head anchor"},
+		{"missing anchor", nil, missingErr, "cow 'tux' has no eyes anchor for 'sunglasses'"},
+		{"wrapped", nil, fmt.Errorf("wear: %w", accessoryErr), "accessory 'cape' not found"},
+		{"range", nil, opts.Validate(), fmt.Sprintf("scale must be between 1 and %d", canvas.MaxScale)},
+		{"image too large", nil, canvas.ErrTooLarge, fmt.Sprintf("image must be at most %d pixels", canvas.MaxPixels)},
+		{"template", nil, templateErr, `invalid message template: message:1: function "nope" not defined`},
+		{"template too long", nil, message.ErrTooLong, fmt.Sprintf("message template expands past %d bytes", message.MaxOutput)},
+		{"qr level", nil, qr.ErrLevel, "QR level must be L, M, Q or H"},
+		{"translated", NewPrinter(Japanese), accessoryErr, "アクセサリー 'cape' が見つかりません"},
+		{"other", NewPrinter(Japanese), errors.New("moo"), "moo"},
+	}
+	for _, tt := range tests {
+		if got := tt.p.Error(tt.err); got != tt.want {
+			t.Errorf("%s: Error() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
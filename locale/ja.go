@@ -0,0 +1,70 @@
+package locale
+
+var ja = map[string]string{
+	"Invalid JSON":               "JSON が不正です",
+	"seed must be an integer":    "seed は整数で指定してください",
+	"text parameter is required": "text パラメータは必須です",
+	"no cows tagged '%s'":        "タグ '%s' の牛はいません",
+
+	"cow '%s' not found":                      "牛 '%s' が見つかりません",
+	"cow '%s' not found, did you mean '%s'?":  "牛 '%s' が見つかりません。もしかして '%s' ですか？",
+	"mood '%s' not found":                     "ムード '%s' が見つかりません",
+	"mood '%s' not found, did you mean '%s'?": "ムード '%s' が見つかりません。もしかして '%s' ですか？",
+
+	"cow '%s' is not available in safe mode":            "牛 '%s' はセーフモードでは使えません",
+	"mood '%s' is not available in safe mode":           "ムード '%s' はセーフモードでは使えません",
+	"offensive fortunes are not available in safe mode": "過激なフォーチュンはセーフモードでは使えません",
+
+	"filter '%s' not found":                              "フィルター '%s' が見つかりません",
+	"font '%s' not found":                                "フォント '%s' が見つかりません",
+	"format '%s' not found":                              "出力形式 '%s' が見つかりません",
+	"cow color '%s' not found":                           "牛の色 '%s' が見つかりません",
+	"cowfile lint found %d errors":                       "cowfile lint で %d 件のエラーが見つかりました",
+	"accessory '%s' not found":                           "アクセサリー '%s' が見つかりません",
+	"invalid message template: %s":                       "メッセージテンプレートが正しくありません: %s",
+	"message template expands past %d bytes":             "メッセージテンプレートの展開結果が %d バイトを超えています",
+	"text for images must be at most %d bytes":           "画像にするテキストは %d バイト以内にしてください",
+	"animation must be at most %d pixels in all frames":  "アニメーションは全フレーム合計 %d ピクセル以内にしてください",
+	"image must be at most %d pixels":                    "画像は %d ピクセル以内にしてください",
+	"accessories '%s' and '%s' both go on the %s anchor": "アクセサリー '%s' と '%s' はどちらも %s のアンカーに付けるものです",
+	"no cows tagged '%s' can wear %s":                    "タグ '%s' の牛で %s を付けられるものはありません",
+	"font family '%s' may only have letters, digits, spaces, commas, hyphens and quotes": "フォントファミリー '%s' に使えるのは文字、数字、空白、カンマ、ハイフン、引用符だけです",
+	"cow '%s' has no %s anchor for '%s'":                                                 "牛 '%s' には %s のアンカーがないため '%s' を付けられません",
+	"cowfile command '%s' not found":                                                     "cowfile コマンド '%s' が見つかりません",
+	"width must be between 1 and %d":                                                     "幅は 1 から %d の間で指定してください",
+	"style '%s' must be ascii or blocks":                                                 "スタイル '%s' は ascii か blocks にしてください",
+	"%s is not a PNG or JPEG image":                                                      "%s は PNG または JPEG 画像ではありません",
+	"position '%s' must be column,row":                                                   "位置 '%s' は 列,行 の形式で指定してください",
+	"theme '%s' not found":                                                               "テーマ '%s' が見つかりません",
+	"graphics '%s' must be sixel, kitty or auto":                                         "グラフィックス '%s' は sixel、kitty、auto のいずれかにしてください",
+	"animation '%s' not found":                                                           "アニメーション '%s' が見つかりません",
+	"font size must be between %d and %d":                                                "フォントサイズは %d から %d の間で指定してください",
+	"scale must be between 1 and %d":                                                     "拡大率は 1 から %d の間で指定してください",
+	"padding must be between 0 and %d":                                                   "余白は 0 から %d の間で指定してください",
+	"color '%s' must be #rgb, #rrggbb, #rrggbbaa or transparent":                         "色 '%s' は #rgb、#rrggbb、#rrggbbaa または transparent で指定してください",
+	"Accessories: %s":                                                                    "アクセサリー: %s",
+	"Fonts: %s":                                                                          "フォント: %s",
+
+	"-animate plays in a terminal or needs -format gif": "-animate は端末で再生するか、-format gif が必要です",
+	"QR level must be L, M, Q or H":                     "QR の誤り訂正レベルは L、M、Q、H のいずれかです",
+	"QR quiet zone must be between 0 and %d":            "QR のクワイエットゾーンは 0 から %d の間で指定してください",
+	"text is too long for a QR code":                    "テキストが長すぎて QR コードにできません",
+
+	"Usage: `/moo [filter:<names>] [banner[:<font>]] [wear:<accessories>] [template] [%s|surprise] [cow] [mood] message`": "使い方: `/moo [filter:<名前>] [banner[:<フォント>]] [wear:<アクセサリー>] [template] [%s|surprise] [牛] [ムード] メッセージ`",
+	"Cows: %s\nMoods: %s\nFilters: %s": "牛: %s\nムード: %s\nフィルター: %s",
+}
+
+var jaMoos = []string{
+	"モー、最高！",
+	"今日もモーれつにがんばろう！",
+	"モーっと好きになっちゃう",
+	"ウシろを振り返らずに進もう！",
+	"うっしっし！",
+	"モーいいかい？",
+	"牛歩でも前に進めばいいんだよ",
+	"牛乳を飲んで元気モリモリ！",
+	"モーすぐ週末だよ！",
+	"隣の牧場の草はいつも青い",
+	"ハッピーモーニング！",
+	"あなたのおかげでモー感激です",
+}
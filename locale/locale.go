@@ -0,0 +1,155 @@
+package locale
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Supported languages, as BCP 47 tags
+const (
+	English             = "en"
+	Japanese            = "ja"
+	BrazilianPortuguese = "pt-BR"
+)
+
+// Supported lists the languages with a message catalog, English first
+var Supported = []string{English, Japanese, BrazilianPortuguese}
+
+// catalog holds the translations of one language, keyed by the English
+// format string, and its random moo messages
+type catalog struct {
+	messages map[string]string
+	moos     []string
+}
+
+var catalogs = map[string]catalog{
+	Japanese:            {messages: ja, moos: jaMoos},
+	BrazilianPortuguese: {messages: ptBR, moos: ptBRMoos},
+}
+
+// Printer formats messages in one language. A nil *Printer formats in
+// English.
+type Printer struct {
+	lang string
+}
+
+// NewPrinter returns a printer for lang, which should be one of Supported,
+// as returned by Match. Unsupported languages print in English.
+func NewPrinter(lang string) *Printer {
+	if _, ok := catalogs[lang]; !ok {
+		lang = English
+	}
+	return &Printer{lang: lang}
+}
+
+// Lang returns the language the printer formats in
+func (p *Printer) Lang() string {
+	if p == nil {
+		return English
+	}
+	return p.lang
+}
+
+// Sprintf formats the translation of format, which is the English text.
+// Messages without a translation are formatted in English.
+func (p *Printer) Sprintf(format string, args ...any) string {
+	if p != nil {
+		if translated, ok := catalogs[p.lang].messages[format]; ok {
+			format = translated
+		}
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+// Messages returns the random moo messages of the printer's language, or
+// nil for English, which uses the built-in moo messages
+func (p *Printer) Messages() []string {
+	if p == nil {
+		return nil
+	}
+	return catalogs[p.lang].moos
+}
+
+// Match returns the supported language that best fits the preferences, in
+// order of preference. Tags match exactly or by base language, so "ja-JP"
+// matches Japanese and "pt" matches Brazilian Portuguese. Without a match
+// it returns English.
+func Match(prefs ...string) string {
+	for _, pref := range prefs {
+		tag := normalize(pref)
+		if tag == "" {
+			continue
+		}
+		for _, lang := range Supported {
+			if strings.EqualFold(tag, lang) {
+				return lang
+			}
+		}
+		base, _, _ := strings.Cut(tag, "-")
+		for _, lang := range Supported {
+			if langBase, _, _ := strings.Cut(lang, "-"); strings.EqualFold(base, langBase) {
+				return lang
+			}
+		}
+	}
+	return English
+}
+
+// ParseAcceptLanguage returns the languages of an HTTP Accept-Language
+// header, most preferred first
+func ParseAcceptLanguage(header string) []string {
+	type weighted struct {
+		tag string
+		q   float64
+	}
+
+	var tags []weighted
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		tag = strings.TrimSpace(tag)
+		if tag == "" || tag == "*" {
+			continue
+		}
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if parsed, err := strconv.ParseFloat(v, 64); err == nil {
+				q = parsed
+			}
+		}
+		if q > 0 {
+			tags = append(tags, weighted{tag, q})
+		}
+	}
+	sort.SliceStable(tags, func(i, j int) bool { return tags[i].q > tags[j].q })
+
+	result := make([]string, len(tags))
+	for i, t := range tags {
+		result[i] = t.tag
+	}
+	return result
+}
+
+// FromEnv returns the language set by the LC_ALL, LC_MESSAGES and LANG
+// environment variables, in that order of precedence
+func FromEnv() string {
+	for _, key := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if v := os.Getenv(key); v != "" {
+			return Match(v)
+		}
+	}
+	return English
+}
+
+// normalize turns a POSIX locale ("pt_BR.UTF-8@euro") into a language tag
+// ("pt-BR"). The "C" and "POSIX" locales have no language.
+func normalize(s string) string {
+	s, _, _ = strings.Cut(s, ".")
+	s, _, _ = strings.Cut(s, "@")
+	if s == "C" || s == "POSIX" {
+		return ""
+	}
+	return strings.ReplaceAll(strings.TrimSpace(s), "_", "-")
+}
@@ -0,0 +1,101 @@
+package locale
+
+import (
+	"regexp"
+	"slices"
+	"testing"
+)
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		prefs []string
+		want  string
+	}{
+		{nil, English},
+		{[]string{"ja"}, Japanese},
+		{[]string{"ja-JP"}, Japanese},
+		{[]string{"ja_JP.UTF-8"}, Japanese},
+		{[]string{"pt_BR.UTF-8"}, BrazilianPortuguese},
+		{[]string{"pt-br"}, BrazilianPortuguese},
+		{[]string{"pt-PT"}, BrazilianPortuguese},
+		{[]string{"fr-FR", "ja"}, Japanese},
+		{[]string{"en-US", "ja"}, English},
+		{[]string{"C"}, English},
+		{[]string{"xx"}, English},
+	}
+
+	for _, tt := range tests {
+		if got := Match(tt.prefs...); got != tt.want {
+			t.Errorf("Match(%q) = %s, want %s", tt.prefs, got, tt.want)
+		}
+	}
+}
+
+func TestParseAcceptLanguage(t *testing.T) {
+	got := ParseAcceptLanguage("fr;q=0.3, pt-BR, ja;q=0.8, *;q=0.1, de;q=0")
+	want := []string{"pt-BR", "ja", "fr"}
+	if !slices.Equal(got, want) {
+		t.Errorf("ParseAcceptLanguage() = %q, want %q", got, want)
+	}
+
+	if got := ParseAcceptLanguage(""); len(got) != 0 {
+		t.Errorf("ParseAcceptLanguage(\"\") = %q, want empty", got)
+	}
+}
+
+func TestFromEnv(t *testing.T) {
+	t.Setenv("LC_ALL", "")
+	t.Setenv("LC_MESSAGES", "")
+	t.Setenv("LANG", "ja_JP.UTF-8")
+	if got := FromEnv(); got != Japanese {
+		t.Errorf("FromEnv() with LANG = %s, want %s", got, Japanese)
+	}
+
+	t.Setenv("LC_ALL", "pt_BR.UTF-8")
+	if got := FromEnv(); got != BrazilianPortuguese {
+		t.Errorf("FromEnv() with LC_ALL = %s, want %s", got, BrazilianPortuguese)
+	}
+}
+
+func TestPrinter(t *testing.T) {
+	var nilPrinter *Printer
+	if got := nilPrinter.Sprintf("cow '%s' not found", "x"); got != "cow 'x' not found" {
+		t.Errorf("nil Sprintf() = %q", got)
+	}
+	if got := NewPrinter("xx").Lang(); got != English {
+		t.Errorf("NewPrinter(xx).Lang() = %s, want %s", got, English)
+	}
+
+	ja := NewPrinter(Japanese)
+	if got := ja.Sprintf("cow '%s' not found", "x"); got != "牛 'x' が見つかりません" {
+		t.Errorf("ja Sprintf() = %q", got)
+	}
+	if got := ja.Sprintf("untranslated %d", 1); got != "untranslated 1" {
+		t.Errorf("untranslated Sprintf() = %q, want the English text", got)
+	}
+	if len(ja.Messages()) == 0 || len(NewPrinter(BrazilianPortuguese).Messages()) == 0 {
+		t.Error("Japanese and Brazilian Portuguese should have moo messages")
+	}
+	if NewPrinter(English).Messages() != nil {
+		t.Error("English should use the built-in moo messages")
+	}
+}
+
+var verb = regexp.MustCompile(`%[a-z]`)
+
+func TestCatalogs(t *testing.T) {
+	for lang, c := range catalogs {
+		for key, translated := range c.messages {
+			if !slices.Equal(verb.FindAllString(key, -1), verb.FindAllString(translated, -1)) {
+				t.Errorf("%s: %q has different verbs than %q", lang, translated, key)
+			}
+		}
+		for other, oc := range catalogs {
+			for key := range oc.messages {
+				if _, ok := c.messages[key]; !ok {
+					t.Errorf("%s is missing %q, translated in %s", lang, key, other)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package locale
+
+var ptBR = map[string]string{
+	"Invalid JSON":               "JSON inválido",
+	"seed must be an integer":    "seed deve ser um número inteiro",
+	"text parameter is required": "o parâmetro text é obrigatório",
+	"no cows tagged '%s'":        "nenhuma vaca com a tag '%s'",
+
+	"cow '%s' not found":                      "vaca '%s' não encontrada",
+	"cow '%s' not found, did you mean '%s'?":  "vaca '%s' não encontrada, você quis dizer '%s'?",
+	"mood '%s' not found":                     "humor '%s' não encontrado",
+	"mood '%s' not found, did you mean '%s'?": "humor '%s' não encontrado, você quis dizer '%s'?",
+
+	"cow '%s' is not available in safe mode":            "a vaca '%s' não está disponível no modo seguro",
+	"mood '%s' is not available in safe mode":           "o humor '%s' não está disponível no modo seguro",
+	"offensive fortunes are not available in safe mode": "fortunes ofensivas não estão disponíveis no modo seguro",
+
+	"filter '%s' not found":                              "filtro '%s' não encontrado",
+	"font '%s' not found":                                "fonte '%s' não encontrada",
+	"format '%s' not found":                              "formato '%s' não encontrado",
+	"cow color '%s' not found":                           "cor de vaca '%s' não encontrada",
+	"cowfile lint found %d errors":                       "cowfile lint encontrou %d erros",
+	"accessory '%s' not found":                           "acessório '%s' não encontrado",
+	"invalid message template: %s":                       "modelo de mensagem inválido: %s",
+	"message template expands past %d bytes":             "o modelo de mensagem passa de %d bytes",
+	"text for images must be at most %d bytes":           "o texto de imagens deve ter no máximo %d bytes",
+	"animation must be at most %d pixels in all frames":  "a animação deve ter no máximo %d pixels somando todos os quadros",
+	"image must be at most %d pixels":                    "a imagem deve ter no máximo %d pixels",
+	"accessories '%s' and '%s' both go on the %s anchor": "os acessórios '%s' e '%s' vão ambos na âncora %s",
+	"no cows tagged '%s' can wear %s":                    "nenhuma vaca com a tag '%s' pode usar %s",
+	"font family '%s' may only have letters, digits, spaces, commas, hyphens and quotes": "a família de fonte '%s' só pode ter letras, dígitos, espaços, vírgulas, hífens e aspas",
+	"cow '%s' has no %s anchor for '%s'":                                                 "a vaca '%s' não tem a âncora %s para '%s'",
+	"cowfile command '%s' not found":                                                     "comando cowfile '%s' não encontrado",
+	"width must be between 1 and %d":                                                     "a largura deve estar entre 1 e %d",
+	"style '%s' must be ascii or blocks":                                                 "o estilo '%s' deve ser ascii ou blocks",
+	"%s is not a PNG or JPEG image":                                                      "%s não é uma imagem PNG ou JPEG",
+	"position '%s' must be column,row":                                                   "a posição '%s' deve ser coluna,linha",
+	"theme '%s' not found":                                                               "tema '%s' não encontrado",
+	"graphics '%s' must be sixel, kitty or auto":                                         "gráficos '%s' devem ser sixel, kitty ou auto",
+	"animation '%s' not found":                                                           "animação '%s' não encontrada",
+	"font size must be between %d and %d":                                                "o tamanho da fonte deve estar entre %d e %d",
+	"scale must be between 1 and %d":                                                     "a escala deve estar entre 1 e %d",
+	"padding must be between 0 and %d":                                                   "a margem deve estar entre 0 e %d",
+	"color '%s' must be #rgb, #rrggbb, #rrggbbaa or transparent":                         "a cor '%s' deve ser #rgb, #rrggbb, #rrggbbaa ou transparent",
+	"Accessories: %s":                                                                    "Acessórios: %s",
+	"Fonts: %s":                                                                          "Fontes: %s",
+
+	"-animate plays in a terminal or needs -format gif": "-animate é exibido no terminal ou precisa de -format gif",
+	"QR level must be L, M, Q or H":                     "o nível do QR deve ser L, M, Q ou H",
+	"QR quiet zone must be between 0 and %d":            "a zona de silêncio do QR deve estar entre 0 e %d",
+	"text is too long for a QR code":                    "o texto é longo demais para um código QR",
+
+	"Usage: `/moo [filter:<names>] [banner[:<font>]] [wear:<accessories>] [template] [%s|surprise] [cow] [mood] message`": "Uso: `/moo [filter:<nomes>] [banner[:<fonte>]] [wear:<acessórios>] [template] [%s|surprise] [vaca] [humor] mensagem`",
+	"Cows: %s\nMoods: %s\nFilters: %s": "Vacas: %s\nHumores: %s\nFiltros: %s",
+}
+
+var ptBRMoos = []string{
+	"Muuuito bom!",
+	"Você é muuuito especial!",
+	"Tenha um dia muuuito feliz!",
+	"Bom dia, flor do pasto!",
+	"Sextou! Muuu!",
+	"Não chore pelo leite derramado",
+	"A grama do vizinho é sempre mais verde",
+	"Você é o queijo da minha goiabada",
+	"Muuuita calma nessa hora!",
+	"Muuuito obrigado!",
+	"Tô de boa no pasto",
+	"Leite quentinho e bom humor!",
+}
@@ -0,0 +1,315 @@
+package message
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"text/template"
+	"text/template/parse"
+	"time"
+)
+
+// MaxOutput is the most bytes a message template may expand to
+const MaxOutput = 16 * 1024
+
+// Built-in variables, which callers may set through Template.Vars
+const (
+	VarUser     = "user"
+	VarChannel  = "channel"
+	VarHostname = "hostname"
+)
+
+// codeFence opens and closes code blocks, which are never expanded
+const codeFence = "```"
+
+// Default layouts of the date and time functions
+const (
+	defaultDateLayout = "2006-01-02"
+	defaultTimeLayout = "15:04"
+)
+
+// Template expands variables in messages, such as
+// "Good morning {{user}}, deploy #{{build}} is {{status}}" or
+// `Today is {{date "Monday"}}`.
+//
+// Every entry in Vars becomes a function of the same name, so {{build}}
+// is replaced by Vars["build"]; {{.build}} works too. The built-in user and
+// channel variables are always defined, empty unless set in Vars, and
+// hostname defaults to the name of the host. The date and time functions
+// format Now with an optional Go time layout.
+//
+// Code blocks between ``` fences are left as they are, so snippets of
+// other template languages can be shown.
+//
+// Templates are sandboxed: only {{if}} and {{with}} blocks are allowed,
+// printf is disabled so widths cannot blow up memory, the output is capped
+// at MaxOutput bytes, and nothing outside Vars is readable
+// unless explicitly allowed. The env function reads only the environment
+// variables listed in Env, and the file function reads only files below
+// Dir.
+type Template struct {
+	Vars map[string]string
+	Now  time.Time
+	Env  []string
+	Dir  string
+}
+
+// reserved names cannot be used as variables: they are functions
+var reserved = map[string]bool{
+	"date": true, "time": true, "env": true, "file": true,
+	"and": true, "or": true, "not": true, "len": true, "index": true, "slice": true,
+	"print": true, "printf": true, "println": true, "html": true, "js": true, "urlquery": true,
+	"eq": true, "ne": true, "lt": true, "le": true, "gt": true, "ge": true, "call": true,
+}
+
+var varName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// ErrTooLong is returned when a template expands past MaxOutput
+var ErrTooLong = fmt.Errorf("template: output exceeds %d bytes", MaxOutput)
+
+// Error is returned by Expand for a template that does not parse, is not
+// allowed or fails to execute. Its text starts with "template: " and says
+// where in the message the error is.
+type Error struct {
+	Err error
+}
+
+func (e *Error) Error() string { return e.Err.Error() }
+
+func (e *Error) Unwrap() error { return e.Err }
+
+// Expand returns text with its template actions replaced. Text without
+// "{{" is returned unchanged. Errors are ErrTooLong or an *Error.
+func (t Template) Expand(text string) (string, error) {
+	if !strings.Contains(text, "{{") {
+		return text, nil
+	}
+	expanded, err := t.expand(text)
+	if err != nil && !errors.Is(err, ErrTooLong) {
+		return "", &Error{Err: err}
+	}
+	return expanded, err
+}
+
+func (t Template) expand(text string) (string, error) {
+
+	funcs, err := t.funcs()
+	if err != nil {
+		return "", err
+	}
+
+	tmpl, err := template.New("message").Funcs(funcs).Option("missingkey=error").Parse(literalCode(text))
+	if err != nil {
+		return "", err
+	}
+	if err := checkSandbox(tmpl); err != nil {
+		return "", fmt.Errorf("template: %w", err)
+	}
+
+	var buf strings.Builder
+	if err := tmpl.Execute(&limitWriter{w: &buf, n: MaxOutput}, t.Vars); err != nil {
+		if errors.Is(err, ErrTooLong) {
+			return "", ErrTooLong
+		}
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// ExpandLines expands message lines as one template, so blocks may span
+// lines. Lines without "{{" are returned unchanged.
+func (t Template) ExpandLines(lines []string) ([]string, error) {
+	text := strings.Join(lines, "\n")
+	if !strings.Contains(text, "{{") {
+		return lines, nil
+	}
+	expanded, err := t.Expand(text)
+	if err != nil {
+		return nil, err
+	}
+	return strings.Split(expanded, "\n"), nil
+}
+
+// literalCode escapes the actions in code blocks, so they are printed as
+// written. An unclosed fence runs to the end of the text.
+func literalCode(text string) string {
+	parts := strings.Split(text, codeFence)
+	for i := 1; i < len(parts); i += 2 {
+		parts[i] = strings.ReplaceAll(parts[i], "{{", `{{"{{"}}`)
+	}
+	return strings.Join(parts, codeFence)
+}
+
+// ParseVars parses "name=value" pairs, as given to the CLI -var flag
+func ParseVars(pairs []string) (map[string]string, error) {
+	vars := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		name, value, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("variable %q: want name=value", pair)
+		}
+		name = strings.TrimSpace(name)
+		if err := checkName(name); err != nil {
+			return nil, err
+		}
+		vars[name] = value
+	}
+	return vars, nil
+}
+
+// hostname returns the host name for the hostname variable, or "" if it
+// cannot be determined
+func hostname() string {
+	name, _ := os.Hostname()
+	return name
+}
+
+// funcs returns the functions available to templates
+func (t Template) funcs() (template.FuncMap, error) {
+	funcs := template.FuncMap{
+		VarUser:     constant(""),
+		VarChannel:  constant(""),
+		VarHostname: hostname,
+		"date":      t.format(defaultDateLayout),
+		"time":      t.format(defaultTimeLayout),
+		"env":       t.env,
+		"file":      t.file,
+		"printf":    disabled("printf"),
+	}
+	for name, value := range t.Vars {
+		if err := checkName(name); err != nil {
+			return nil, err
+		}
+		funcs[name] = constant(value)
+	}
+	return funcs, nil
+}
+
+// format returns a function formatting Now with an optional layout
+func (t Template) format(layout string) func(...string) (string, error) {
+	return func(args ...string) (string, error) {
+		switch len(args) {
+		case 0:
+			return t.Now.Format(layout), nil
+		case 1:
+			return t.Now.Format(args[0]), nil
+		}
+		return "", errors.New("want at most one layout")
+	}
+}
+
+// env reads an environment variable, if allowed
+func (t Template) env(name string) (string, error) {
+	for _, allowed := range t.Env {
+		if name == allowed {
+			return os.Getenv(name), nil
+		}
+	}
+	return "", fmt.Errorf("environment variable %q is not allowed", name)
+}
+
+// file reads a file below Dir, if a directory is allowed
+func (t Template) file(name string) (string, error) {
+	if t.Dir == "" {
+		return "", errors.New("reading files is not allowed")
+	}
+	root, err := os.OpenRoot(t.Dir)
+	if err != nil {
+		return "", err
+	}
+	defer root.Close()
+
+	f, err := root.Open(name)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	data, err := io.ReadAll(io.LimitReader(f, MaxOutput+1))
+	if err != nil {
+		return "", err
+	}
+	if len(data) > MaxOutput {
+		return "", ErrTooLong
+	}
+	return strings.TrimRight(string(data), "\n"), nil
+}
+
+// checkName reports whether name can be used as a variable
+func checkName(name string) error {
+	if !varName.MatchString(name) {
+		return fmt.Errorf("variable %q: names are letters, digits and underscores", name)
+	}
+	if reserved[name] {
+		return fmt.Errorf("variable %q: name is reserved", name)
+	}
+	return nil
+}
+
+// checkSandbox rejects templates that could run unbounded: loops and
+// template definitions and calls
+func checkSandbox(tmpl *template.Template) error {
+	if len(tmpl.Templates()) > 1 {
+		return errors.New("template definitions are not allowed")
+	}
+	return walk(tmpl.Tree.Root)
+}
+
+func walk(node parse.Node) error {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return nil
+		}
+		for _, child := range n.Nodes {
+			if err := walk(child); err != nil {
+				return err
+			}
+		}
+	case *parse.IfNode:
+		return walkBranch(&n.BranchNode)
+	case *parse.WithNode:
+		return walkBranch(&n.BranchNode)
+	case *parse.RangeNode:
+		return errors.New("range is not allowed")
+	case *parse.TemplateNode:
+		return errors.New("template calls are not allowed")
+	}
+	return nil
+}
+
+func walkBranch(b *parse.BranchNode) error {
+	if err := walk(b.List); err != nil {
+		return err
+	}
+	return walk(b.ElseList)
+}
+
+// constant returns a template function that returns value
+func constant(value string) func() string {
+	return func() string { return value }
+}
+
+// disabled returns a template function that always fails
+func disabled(name string) func(...any) (string, error) {
+	return func(...any) (string, error) {
+		return "", fmt.Errorf("%s is not allowed", name)
+	}
+}
+
+// limitWriter fails writes past n bytes
+type limitWriter struct {
+	w io.Writer
+	n int
+}
+
+func (l *limitWriter) Write(p []byte) (int, error) {
+	if len(p) > l.n {
+		return 0, ErrTooLong
+	}
+	l.n -= len(p)
+	return l.w.Write(p)
+}
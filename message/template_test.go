@@ -0,0 +1,127 @@
+package message
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTemplate_Expand(t *testing.T) {
+	tmpl := Template{
+		Vars: map[string]string{"user": "alice", "build": "42", "status": "green"},
+		Now:  time.Date(2026, 10, 19, 9, 30, 0, 0, time.UTC),
+	}
+
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"plain text", "plain text"},
+		{"Good morning {{user}}, deploy #{{build}} is {{status}}", "Good morning alice, deploy #42 is green"},
+		{`Today is {{date "Monday"}}`, "Today is Monday"},
+		{"{{date}} {{time}}", "2026-10-19 09:30"},
+		{"{{.build}}", "42"},
+		{`{{if eq status "green"}}ship it{{else}}hold{{end}}`, "ship it"},
+		{"in {{channel}}", "in "},
+		{"{{user}}: ```{{ .Values.image }}```", "alice: ```{{ .Values.image }}```"},
+		{"```yaml\nimage: {{ .Values.image }}\n```\nby {{user}}", "```yaml\nimage: {{ .Values.image }}\n```\nby alice"},
+		{"{{if user}}```{{x}}```{{end}}", "```{{x}}```"},
+		{"```{{ unclosed }}", "```{{ unclosed }}"},
+	}
+
+	for _, tt := range tests {
+		got, err := tmpl.Expand(tt.text)
+		if err != nil {
+			t.Errorf("Expand(%q) error = %v", tt.text, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Expand(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestTemplate_Sandbox(t *testing.T) {
+	t.Setenv("GOWSAY_TEST_SECRET", "hunter2")
+
+	tests := []struct {
+		name string
+		text string
+	}{
+		{"undefined variable", "{{nope}}"},
+		{"environment not allowed", `{{env "GOWSAY_TEST_SECRET"}}`},
+		{"files not allowed", `{{file "/etc/passwd"}}`},
+		{"range", "{{range 1000000000}}moo{{end}}"},
+		{"define", `{{define "x"}}moo{{end}}`},
+		{"printf", `{{printf "%0999999999d" 1}}`},
+		{"syntax error", "{{user"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var tmplErr *Error
+			if got, err := (Template{}).Expand(tt.text); !errors.As(err, &tmplErr) {
+				t.Errorf("Expand(%q) = %q, %v, want an *Error", tt.text, got, err)
+			}
+		})
+	}
+}
+
+func TestTemplate_Allowed(t *testing.T) {
+	t.Setenv("GOWSAY_TEST_BUILD", "99")
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "motd"), []byte("Moo from disk\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tmpl := Template{Env: []string{"GOWSAY_TEST_BUILD"}, Dir: dir}
+	got, err := tmpl.Expand(`{{env "GOWSAY_TEST_BUILD"}}: {{file "motd"}}`)
+	if err != nil {
+		t.Fatalf("Expand() error = %v", err)
+	}
+	if got != "99: Moo from disk" {
+		t.Errorf("Expand() = %q, want %q", got, "99: Moo from disk")
+	}
+
+	if _, err := tmpl.Expand(`{{file "../outside"}}`); err == nil {
+		t.Error("file outside Dir should not be readable")
+	}
+}
+
+func TestTemplate_MaxOutput(t *testing.T) {
+	tmpl := Template{Vars: map[string]string{"big": strings.Repeat("x", MaxOutput/2+1)}}
+	if _, err := tmpl.Expand("{{big}}{{big}}"); !errors.Is(err, ErrTooLong) {
+		t.Errorf("Expand() past MaxOutput error = %v, want ErrTooLong", err)
+	}
+}
+
+func TestTemplate_ExpandLines(t *testing.T) {
+	tmpl := Template{Vars: map[string]string{"ok": "yes"}}
+
+	got, err := tmpl.ExpandLines([]string{"{{if ok}}all", "good{{end}}"})
+	if err != nil {
+		t.Fatalf("ExpandLines() error = %v", err)
+	}
+	if len(got) != 2 || got[0] != "all" || got[1] != "good" {
+		t.Errorf("ExpandLines() = %q, want [all good]", got)
+	}
+}
+
+func TestParseVars(t *testing.T) {
+	vars, err := ParseVars([]string{"build=42", "status=a=b", "empty="})
+	if err != nil {
+		t.Fatalf("ParseVars() error = %v", err)
+	}
+	if vars["build"] != "42" || vars["status"] != "a=b" || vars["empty"] != "" {
+		t.Errorf("ParseVars() = %v", vars)
+	}
+
+	for _, bad := range []string{"build", "1x=2", "date=today", "a-b=c"} {
+		if _, err := ParseVars([]string{bad}); err == nil {
+			t.Errorf("ParseVars(%q) should fail", bad)
+		}
+	}
+}
@@ -0,0 +1,388 @@
+package qr
+
+import (
+	"errors"
+	"fmt"
+)
+
+// A small QR decoder for tests, written from ISO/IEC 18004 rather than from
+// the encoder: it reads half-block output back into modules, checks the
+// function patterns, format and version information and error correction,
+// and reads byte mode data. It only knows the block layouts of versions 1
+// to 10.
+
+// specAlignment lists alignment pattern centres by version
+var specAlignment = map[int][]int{
+	1: nil, 2: {6, 18}, 3: {6, 22}, 4: {6, 26}, 5: {6, 30}, 6: {6, 34},
+	7: {6, 22, 38}, 8: {6, 24, 42}, 9: {6, 26, 46}, 10: {6, 28, 50},
+	14: {6, 26, 46, 66}, 20: {6, 34, 62, 90},
+	32: {6, 34, 60, 86, 112, 138}, 36: {6, 24, 50, 76, 102, 128, 154},
+	40: {6, 30, 58, 86, 114, 142, 170},
+}
+
+// blockGroup is a run of error correction blocks with the same data length
+type blockGroup struct{ blocks, data int }
+
+// specBlocks lists error correction codewords per block and the block
+// groups, by version and level (L, M, Q, H)
+var specBlocks = map[int][4]struct {
+	ecc    int
+	groups []blockGroup
+}{
+	1:  {{7, []blockGroup{{1, 19}}}, {10, []blockGroup{{1, 16}}}, {13, []blockGroup{{1, 13}}}, {17, []blockGroup{{1, 9}}}},
+	2:  {{10, []blockGroup{{1, 34}}}, {16, []blockGroup{{1, 28}}}, {22, []blockGroup{{1, 22}}}, {28, []blockGroup{{1, 16}}}},
+	3:  {{15, []blockGroup{{1, 55}}}, {26, []blockGroup{{1, 44}}}, {18, []blockGroup{{2, 17}}}, {22, []blockGroup{{2, 13}}}},
+	4:  {{20, []blockGroup{{1, 80}}}, {18, []blockGroup{{2, 32}}}, {26, []blockGroup{{2, 24}}}, {16, []blockGroup{{4, 9}}}},
+	5:  {{26, []blockGroup{{1, 108}}}, {24, []blockGroup{{2, 43}}}, {18, []blockGroup{{2, 15}, {2, 16}}}, {22, []blockGroup{{2, 11}, {2, 12}}}},
+	6:  {{18, []blockGroup{{2, 68}}}, {16, []blockGroup{{4, 27}}}, {24, []blockGroup{{4, 19}}}, {28, []blockGroup{{4, 15}}}},
+	7:  {{20, []blockGroup{{2, 78}}}, {18, []blockGroup{{4, 31}}}, {18, []blockGroup{{2, 14}, {4, 15}}}, {26, []blockGroup{{4, 13}, {1, 14}}}},
+	8:  {{24, []blockGroup{{2, 97}}}, {22, []blockGroup{{2, 38}, {2, 39}}}, {22, []blockGroup{{4, 18}, {2, 19}}}, {26, []blockGroup{{4, 14}, {2, 15}}}},
+	9:  {{30, []blockGroup{{2, 116}}}, {22, []blockGroup{{3, 36}, {2, 37}}}, {20, []blockGroup{{4, 16}, {4, 17}}}, {24, []blockGroup{{4, 12}, {4, 13}}}},
+	10: {{18, []blockGroup{{2, 68}, {2, 69}}}, {26, []blockGroup{{4, 43}, {1, 44}}}, {24, []blockGroup{{6, 19}, {2, 20}}}, {28, []blockGroup{{6, 15}, {2, 16}}}},
+}
+
+// decoded is what decode read from a code
+type decoded struct {
+	text    string
+	level   Level
+	version int
+	mask    int
+	quiet   int
+}
+
+// decode reads a code drawn by Lines
+func decode(lines []string, invert bool) (decoded, error) {
+	var d decoded
+
+	// Unpack the half blocks into rows of modules
+	var grid [][]bool
+	for _, line := range lines {
+		top, bottom := []bool{}, []bool{}
+		for _, r := range line {
+			var t, b bool
+			switch r {
+			case ' ':
+			case '▀':
+				t = true
+			case '▄':
+				b = true
+			case '█':
+				t, b = true, true
+			default:
+				return d, fmt.Errorf("unexpected character %q", r)
+			}
+			top, bottom = append(top, t != invert), append(bottom, b != invert)
+		}
+		grid = append(grid, top, bottom)
+	}
+
+	// The code is the square around the dark modules; the rest must be light
+	minX, minY, maxX, maxY := len(grid), len(grid), -1, -1
+	for y, row := range grid {
+		for x, dark := range row {
+			if dark {
+				minX, minY, maxX, maxY = min(minX, x), min(minY, y), max(maxX, x), max(maxY, y)
+			}
+		}
+	}
+	size := maxX - minX + 1
+	if size != maxY-minY+1 || size < 21 || (size-17)%4 != 0 {
+		return d, fmt.Errorf("code is %dx%d", size, maxY-minY+1)
+	}
+	if minX != minY || len(grid[0])-1-maxX != minX || len(grid)-1-maxY < minY {
+		return d, errors.New("quiet zone is not even")
+	}
+	d.quiet = minX
+	d.version = (size - 17) / 4
+	at := func(x, y int) bool { return grid[minY+y][minX+x] }
+
+	// Finder patterns
+	for _, corner := range [][2]int{{0, 0}, {size - 7, 0}, {0, size - 7}} {
+		for y := range 7 {
+			for x := range 7 {
+				ring := min(x, y, 6-x, 6-y)
+				if at(corner[0]+x, corner[1]+y) != (ring != 1) {
+					return d, fmt.Errorf("bad finder pattern at %v", corner)
+				}
+			}
+		}
+	}
+
+	// Format information, both copies
+	var format1, format2 int
+	for i := range 15 {
+		var x1, y1, x2, y2 int
+		switch {
+		case i < 6:
+			x1, y1 = 8, i
+		case i < 8:
+			x1, y1 = 8, i+1
+		case i == 8:
+			x1, y1 = 7, 8
+		default:
+			x1, y1 = 14-i, 8
+		}
+		if i < 8 {
+			x2, y2 = size-1-i, 8
+		} else {
+			x2, y2 = 8, size-15+i
+		}
+		if at(x1, y1) {
+			format1 |= 1 << i
+		}
+		if at(x2, y2) {
+			format2 |= 1 << i
+		}
+	}
+	if format1 != format2 {
+		return d, fmt.Errorf("format copies differ: %015b %015b", format1, format2)
+	}
+	format := format1 ^ 0x5412
+	if bchRemainder(format, 0x537) != 0 {
+		return d, fmt.Errorf("bad format information %015b", format)
+	}
+	d.level = [4]Level{Medium, Low, High, Quartile}[format>>13]
+	d.mask = format >> 10 & 7
+	if !at(8, size-8) {
+		return d, errors.New("dark module is light")
+	}
+
+	// Version information, both copies
+	if d.version >= 7 {
+		var v1, v2 int
+		for i := range 18 {
+			if at(size-11+i%3, i/3) {
+				v1 |= 1 << i
+			}
+			if at(i/3, size-11+i%3) {
+				v2 |= 1 << i
+			}
+		}
+		if v1 != v2 || bchRemainder(v1, 0x1F25) != 0 || v1>>12 != d.version {
+			return d, fmt.Errorf("bad version information %018b %018b", v1, v2)
+		}
+	}
+
+	// Timing patterns
+	for i := 8; i < size-8; i++ {
+		if at(i, 6) != (i%2 == 0) || at(6, i) != (i%2 == 0) {
+			return d, errors.New("bad timing pattern")
+		}
+	}
+
+	// Function modules, which hold no data
+	function := make([][]bool, size)
+	for y := range function {
+		function[y] = make([]bool, size)
+	}
+	reserve := func(x0, y0, w, h int) {
+		for y := y0; y < y0+h; y++ {
+			for x := x0; x < x0+w; x++ {
+				function[y][x] = true
+			}
+		}
+	}
+	reserve(0, 0, 9, 9)
+	reserve(size-8, 0, 8, 9)
+	reserve(0, size-8, 9, 8)
+	reserve(6, 0, 1, size)
+	reserve(0, 6, size, 1)
+	if d.version >= 7 {
+		reserve(size-11, 0, 3, 6)
+		reserve(0, size-11, 6, 3)
+	}
+	centres, ok := specAlignment[d.version]
+	if !ok {
+		return d, fmt.Errorf("version %d is not supported", d.version)
+	}
+	for _, cy := range centres {
+		for _, cx := range centres {
+			// No alignment patterns overlap the finders
+			if cx < 9 && cy < 9 || cx >= size-9 && cy < 9 || cx < 9 && cy >= size-9 {
+				continue
+			}
+			for y := -2; y <= 2; y++ {
+				for x := -2; x <= 2; x++ {
+					if at(cx+x, cy+y) != (max(x, -x, y, -y) != 1) {
+						return d, fmt.Errorf("bad alignment pattern at %d,%d", cx, cy)
+					}
+				}
+			}
+			reserve(cx-2, cy-2, 5, 5)
+		}
+	}
+
+	// Data bits in the zigzag, unmasked
+	var codewords []byte
+	var bit int
+	upward := true
+	for right := size - 1; right > 0; right, upward = right-2, !upward {
+		if right == 6 {
+			right--
+		}
+		for vert := range size {
+			y := vert
+			if upward {
+				y = size - 1 - vert
+			}
+			for _, x := range []int{right, right - 1} {
+				if function[y][x] {
+					continue
+				}
+				if bit%8 == 0 {
+					codewords = append(codewords, 0)
+				}
+				if at(x, y) != specMask(d.mask, y, x) {
+					codewords[bit/8] |= 0x80 >> (bit % 8)
+				}
+				bit++
+			}
+		}
+	}
+
+	// De-interleave the blocks and check their error correction
+	layout, ok := specBlocks[d.version]
+	if !ok {
+		return d, fmt.Errorf("version %d is not supported", d.version)
+	}
+	spec := layout[d.level]
+	var blocks [][]byte
+	longest := 0
+	for _, g := range spec.groups {
+		for range g.blocks {
+			blocks = append(blocks, make([]byte, 0, g.data+spec.ecc))
+		}
+		longest = max(longest, g.data)
+	}
+	k := 0
+	for i := range longest {
+		for b := range blocks {
+			if i < cap(blocks[b])-spec.ecc {
+				blocks[b] = append(blocks[b], codewords[k])
+				k++
+			}
+		}
+	}
+	for range spec.ecc {
+		for b := range blocks {
+			blocks[b] = append(blocks[b], codewords[k])
+			k++
+		}
+	}
+	var data []byte
+	for b, block := range blocks {
+		if !syndromesZero(block, spec.ecc) {
+			return d, fmt.Errorf("block %d fails error correction", b)
+		}
+		data = append(data, block[:len(block)-spec.ecc]...)
+	}
+
+	// Byte mode segment, terminator and padding
+	read := func(pos, n int) int {
+		v := 0
+		for i := pos; i < pos+n; i++ {
+			v = v<<1 | int(data[i/8]>>(7-i%8)&1)
+		}
+		return v
+	}
+	if mode := read(0, 4); mode != 0b0100 {
+		return d, fmt.Errorf("mode %04b, want byte mode", mode)
+	}
+	countLen := 8
+	if d.version >= 10 {
+		countLen = 16
+	}
+	count := read(4, countLen)
+	pos := 4 + countLen
+	if pos+count*8 > len(data)*8 {
+		return d, fmt.Errorf("count %d overflows the data", count)
+	}
+	text := make([]byte, count)
+	for i := range text {
+		text[i] = byte(read(pos, 8))
+		pos += 8
+	}
+	d.text = string(text)
+
+	if end := min(pos+4, len(data)*8); read(pos, end-pos) != 0 {
+		return d, errors.New("missing terminator")
+	}
+	for i, pad := (pos+7)/8, byte(0xEC); i < len(data); i, pad = i+1, pad^0xEC^0x11 {
+		if data[i] != pad {
+			return d, fmt.Errorf("bad padding %#x at %d", data[i], i)
+		}
+	}
+	return d, nil
+}
+
+// specMask reports whether a mask pattern inverts the module at row i,
+// column j, using the standard's formulas
+func specMask(mask, i, j int) bool {
+	switch mask {
+	case 0b000:
+		return (i+j)%2 == 0
+	case 0b001:
+		return i%2 == 0
+	case 0b010:
+		return j%3 == 0
+	case 0b011:
+		return (i+j)%3 == 0
+	case 0b100:
+		return (i/2+j/3)%2 == 0
+	case 0b101:
+		return (i*j)%2+(i*j)%3 == 0
+	case 0b110:
+		return ((i*j)%2+(i*j)%3)%2 == 0
+	default:
+		return ((i*j)%3+(i+j)%2)%2 == 0
+	}
+}
+
+// bchRemainder divides the bits by the generator polynomial over GF(2)
+func bchRemainder(bits, generator int) int {
+	degree := 0
+	for g := generator; g > 1; g >>= 1 {
+		degree++
+	}
+	for i := 31; i >= degree; i-- {
+		if bits>>i&1 != 0 {
+			bits ^= generator << (i - degree)
+		}
+	}
+	return bits
+}
+
+// syndromesZero evaluates a block, as a polynomial, at the roots of the
+// generator a^0 ... a^(ecc-1); all are zero for an intact block
+func syndromesZero(block []byte, ecc int) bool {
+	// Log and antilog tables of GF(256) with the 0x11D polynomial
+	var exp [255]byte
+	x := 1
+	for i := range exp {
+		exp[i] = byte(x)
+		if x <<= 1; x&0x100 != 0 {
+			x ^= 0x11D
+		}
+	}
+	var log [256]int
+	for i, v := range exp {
+		log[v] = i
+	}
+	mul := func(a, b byte) byte {
+		if a == 0 || b == 0 {
+			return 0
+		}
+		return exp[(log[a]+log[b])%255]
+	}
+
+	for i := range ecc {
+		var s byte
+		for _, c := range block {
+			s = mul(s, exp[i]) ^ c
+		}
+		if s != 0 {
+			return false
+		}
+	}
+	return true
+}
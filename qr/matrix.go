@@ -0,0 +1,330 @@
+package qr
+
+// newCode returns an empty code of the given version, with its function
+// patterns drawn and reserved
+func newCode(version int, level Level) *Code {
+	size := version*4 + 17
+	c := &Code{Version: version, Level: level, size: size}
+	c.modules = make([][]bool, size)
+	for y := range c.modules {
+		c.modules[y] = make([]bool, size)
+	}
+	return c
+}
+
+// draw places the codewords, picks the mask with the lowest penalty and
+// applies it
+func (c *Code) draw(codewords []byte) {
+	function := c.drawFunctionPatterns()
+	c.drawCodewords(codewords, function)
+
+	best, bestPenalty := 0, -1
+	for mask := range 8 {
+		c.applyMask(mask, function)
+		c.drawFormatBits(mask)
+		if p := c.penalty(); bestPenalty < 0 || p < bestPenalty {
+			best, bestPenalty = mask, p
+		}
+		c.applyMask(mask, function)
+	}
+	c.Mask = best
+	c.applyMask(best, function)
+	c.drawFormatBits(best)
+}
+
+// drawFunctionPatterns draws the finder, timing and alignment patterns and
+// the version information, and returns the modules they and the format
+// information reserve
+func (c *Code) drawFunctionPatterns() [][]bool {
+	size := c.size
+	function := make([][]bool, size)
+	for y := range function {
+		function[y] = make([]bool, size)
+	}
+	set := func(x, y int, dark bool) {
+		c.modules[y][x] = dark
+		function[y][x] = true
+	}
+
+	// Timing patterns
+	for i := range size {
+		set(6, i, i%2 == 0)
+		set(i, 6, i%2 == 0)
+	}
+
+	// Finder patterns with their separators
+	for _, corner := range [][2]int{{3, 3}, {size - 4, 3}, {3, size - 4}} {
+		for dy := -4; dy <= 4; dy++ {
+			for dx := -4; dx <= 4; dx++ {
+				x, y := corner[0]+dx, corner[1]+dy
+				if x < 0 || x >= size || y < 0 || y >= size {
+					continue
+				}
+				d := max(abs(dx), abs(dy))
+				set(x, y, d != 2 && d != 4)
+			}
+		}
+	}
+
+	// Alignment patterns, except where they would overlap finders
+	positions := alignmentPositions(c.Version)
+	last := len(positions) - 1
+	for i, cy := range positions {
+		for j, cx := range positions {
+			if i == 0 && j == 0 || i == 0 && j == last || i == last && j == 0 {
+				continue
+			}
+			for dy := -2; dy <= 2; dy++ {
+				for dx := -2; dx <= 2; dx++ {
+					set(cx+dx, cy+dy, max(abs(dx), abs(dy)) != 1)
+				}
+			}
+		}
+	}
+
+	// Format information is drawn per mask; reserve its modules and
+	// the dark module now
+	for i := range 9 {
+		function[8][i] = true
+		function[i][8] = true
+	}
+	for i := range 8 {
+		function[8][size-1-i] = true
+		function[size-1-i][8] = true
+	}
+
+	// Version information
+	if c.Version >= 7 {
+		bits := versionBits(c.Version)
+		for i := range 18 {
+			dark := bits>>i&1 != 0
+			a, b := size-11+i%3, i/3
+			set(a, b, dark)
+			set(b, a, dark)
+		}
+	}
+	return function
+}
+
+// drawFormatBits draws both copies of the format information for the
+// code's level and the mask
+func (c *Code) drawFormatBits(mask int) {
+	bits := formatBits(c.Level, mask)
+	bit := func(i int) bool { return bits>>i&1 != 0 }
+	size := c.size
+
+	// Around the top left finder
+	for i := range 6 {
+		c.modules[i][8] = bit(i)
+	}
+	c.modules[7][8] = bit(6)
+	c.modules[8][8] = bit(7)
+	c.modules[8][7] = bit(8)
+	for i := 9; i < 15; i++ {
+		c.modules[8][14-i] = bit(i)
+	}
+
+	// Split between the other two finders
+	for i := range 8 {
+		c.modules[8][size-1-i] = bit(i)
+	}
+	for i := 8; i < 15; i++ {
+		c.modules[size-15+i][8] = bit(i)
+	}
+	c.modules[size-8][8] = true
+}
+
+// drawCodewords places the codewords' bits in the two-module-wide zigzag,
+// right to left, skipping function patterns
+func (c *Code) drawCodewords(codewords []byte, function [][]bool) {
+	i := 0
+	for right := c.size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			// Skip the vertical timing pattern
+			right = 5
+		}
+		upward := (right+1)&2 == 0
+		for vert := range c.size {
+			y := vert
+			if upward {
+				y = c.size - 1 - vert
+			}
+			for j := range 2 {
+				x := right - j
+				if function[y][x] || i >= len(codewords)*8 {
+					continue
+				}
+				c.modules[y][x] = codewords[i/8]>>(7-i%8)&1 != 0
+				i++
+			}
+		}
+	}
+}
+
+// applyMask flips the data modules the mask selects. Applying a mask twice
+// undoes it.
+func (c *Code) applyMask(mask int, function [][]bool) {
+	for y := range c.size {
+		for x := range c.size {
+			if !function[y][x] && masked(mask, x, y) {
+				c.modules[y][x] = !c.modules[y][x]
+			}
+		}
+	}
+}
+
+// masked reports whether a mask pattern flips the module at x, y
+func masked(mask, x, y int) bool {
+	switch mask {
+	case 0:
+		return (x+y)%2 == 0
+	case 1:
+		return y%2 == 0
+	case 2:
+		return x%3 == 0
+	case 3:
+		return (x+y)%3 == 0
+	case 4:
+		return (x/3+y/2)%2 == 0
+	case 5:
+		return x*y%2+x*y%3 == 0
+	case 6:
+		return (x*y%2+x*y%3)%2 == 0
+	default:
+		return ((x+y)%2+x*y%3)%2 == 0
+	}
+}
+
+// Penalty weights for mask selection
+const (
+	penaltyRun     = 3
+	penaltyBox     = 3
+	penaltyFinder  = 40
+	penaltyBalance = 10
+)
+
+// penalty scores how hard the code is to scan: long runs, 2x2 boxes,
+// finder-like patterns and an unbalanced dark/light ratio
+func (c *Code) penalty() int {
+	size := c.size
+	total := 0
+	line := make([]bool, size)
+
+	for _, horizontal := range []bool{true, false} {
+		for i := range size {
+			for j := range size {
+				if horizontal {
+					line[j] = c.modules[i][j]
+				} else {
+					line[j] = c.modules[j][i]
+				}
+			}
+			total += linePenalty(line)
+		}
+	}
+
+	dark := 0
+	for y := range size {
+		for x := range size {
+			if c.modules[y][x] {
+				dark++
+			}
+			if x > 0 && y > 0 {
+				m := c.modules[y][x]
+				if c.modules[y-1][x] == m && c.modules[y][x-1] == m && c.modules[y-1][x-1] == m {
+					total += penaltyBox
+				}
+			}
+		}
+	}
+
+	percent := dark * 100 / (size * size)
+	total += abs(percent-50) / 5 * penaltyBalance
+	return total
+}
+
+// finderLike is the 1:1:3:1:1 finder pattern with four light modules on
+// one side
+var finderLike = [][]bool{
+	{true, false, true, true, true, false, true, false, false, false, false},
+	{false, false, false, false, true, false, true, true, true, false, true},
+}
+
+// linePenalty scores runs and finder-like patterns in a row or column
+func linePenalty(line []bool) int {
+	total := 0
+	run := 1
+	for i := 1; i <= len(line); i++ {
+		if i < len(line) && line[i] == line[i-1] {
+			run++
+			continue
+		}
+		if run >= 5 {
+			total += penaltyRun + run - 5
+		}
+		run = 1
+	}
+
+	for i := 0; i+len(finderLike[0]) <= len(line); i++ {
+		for _, pattern := range finderLike {
+			match := true
+			for k, dark := range pattern {
+				if line[i+k] != dark {
+					match = false
+					break
+				}
+			}
+			if match {
+				total += penaltyFinder
+			}
+		}
+	}
+	return total
+}
+
+// alignmentPositions returns the centre coordinates of the alignment
+// patterns, used for both rows and columns
+func alignmentPositions(version int) []int {
+	if version == 1 {
+		return nil
+	}
+	count := version/7 + 2
+	step := 26
+	if version != 32 {
+		step = (version*4 + count*2 + 1) / (count*2 - 2) * 2
+	}
+	positions := make([]int, count)
+	positions[0] = 6
+	for i, pos := count-1, version*4+10; i >= 1; i, pos = i-1, pos-step {
+		positions[i] = pos
+	}
+	return positions
+}
+
+// formatBits returns the 15 format information bits: the level and mask,
+// a BCH(15,5) code, and the fixed XOR mask
+func formatBits(level Level, mask int) int {
+	data := level.formatBits()<<3 | mask
+	rem := data
+	for range 10 {
+		rem = rem<<1 ^ (rem>>9)*0x537
+	}
+	return (data<<10 | rem) ^ 0x5412
+}
+
+// versionBits returns the 18 version information bits: the version and a
+// BCH(18,6) code
+func versionBits(version int) int {
+	rem := version
+	for range 12 {
+		rem = rem<<1 ^ (rem>>11)*0x1F25
+	}
+	return version<<12 | rem
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,228 @@
+package qr
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Level is an error correction level. Higher levels survive more damage
+// but hold less data.
+type Level int
+
+// Error correction levels, recovering about 7%, 15%, 25% and 30% of the
+// code
+const (
+	Low Level = iota
+	Medium
+	Quartile
+	High
+)
+
+// DefaultQuietZone is the width in modules of the light border scanners
+// need around a code
+const DefaultQuietZone = 4
+
+// MaxQuietZone is the widest quiet zone Encode accepts
+const MaxQuietZone = 16
+
+// Errors for data that does not fit in the largest code, an unknown level
+// letter and a quiet zone outside 0 to MaxQuietZone
+var (
+	ErrTooLong   = errors.New("qr: data too long for a QR code")
+	ErrLevel     = errors.New("qr: level must be L, M, Q or H")
+	ErrQuietZone = fmt.Errorf("qr: quiet zone must be between 0 and %d", MaxQuietZone)
+)
+
+// String returns the level's letter: L, M, Q or H
+func (l Level) String() string {
+	return string("LMQH"[l])
+}
+
+// formatBits returns the level's two-bit code in the format information
+func (l Level) formatBits() int {
+	return [...]int{1, 0, 3, 2}[l]
+}
+
+// ParseLevel parses a level letter (L, M, Q or H), in either case
+func ParseLevel(s string) (Level, error) {
+	if i := strings.Index("LMQH", strings.ToUpper(s)); len(s) == 1 && i >= 0 {
+		return Level(i), nil
+	}
+	return 0, fmt.Errorf("%w, not %q", ErrLevel, s)
+}
+
+// Options control how a code is encoded and drawn
+type Options struct {
+	Level Level
+	// QuietZone is the light border, in modules, drawn around the code
+	QuietZone int
+	// Invert draws dark modules as blanks and light modules as blocks,
+	// for terminals with light text on a dark background
+	Invert bool
+}
+
+// Code is an encoded QR code
+type Code struct {
+	Version int
+	Level   Level
+	Mask    int
+	size    int
+	modules [][]bool
+	opts    Options
+}
+
+// Encode encodes data in byte mode in the smallest code that holds it at
+// the options' level. The quiet zone must be between 0 and MaxQuietZone.
+func Encode(data string, opts Options) (*Code, error) {
+	if opts.QuietZone < 0 || opts.QuietZone > MaxQuietZone {
+		return nil, ErrQuietZone
+	}
+	for version := 1; version <= 40; version++ {
+		if len(data) > capacity(version, opts.Level) {
+			continue
+		}
+		codewords := addECC(encodeData(data, version, opts.Level), version, opts.Level)
+		c := newCode(version, opts.Level)
+		c.opts = opts
+		c.draw(codewords)
+		return c, nil
+	}
+	return nil, ErrTooLong
+}
+
+// Size returns the width and height of the code in modules, without the
+// quiet zone
+func (c *Code) Size() int {
+	return c.size
+}
+
+// Dark reports whether the module at column x, row y is dark
+func (c *Code) Dark(x, y int) bool {
+	return c.modules[y][x]
+}
+
+// Tables from ISO/IEC 18004, indexed by level then version
+var (
+	eccPerBlock = [4][41]int{
+		{-1, 7, 10, 15, 20, 26, 18, 20, 24, 30, 18, 20, 24, 26, 30, 22, 24, 28, 30, 28, 28, 28, 28, 30, 30, 26, 28, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+		{-1, 10, 16, 26, 18, 24, 16, 18, 22, 22, 26, 30, 22, 22, 24, 24, 28, 28, 26, 26, 26, 26, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28},
+		{-1, 13, 22, 18, 26, 18, 24, 18, 22, 20, 24, 28, 26, 24, 20, 30, 24, 28, 28, 26, 30, 28, 30, 30, 30, 30, 28, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+		{-1, 17, 28, 22, 16, 22, 28, 26, 26, 24, 28, 24, 28, 22, 24, 24, 30, 28, 28, 26, 28, 30, 24, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+	}
+	eccBlocks = [4][41]int{
+		{-1, 1, 1, 1, 1, 1, 2, 2, 2, 2, 4, 4, 4, 4, 4, 6, 6, 6, 6, 7, 8, 8, 9, 9, 10, 12, 12, 12, 13, 14, 15, 16, 17, 18, 19, 19, 20, 21, 22, 24, 25},
+		{-1, 1, 1, 1, 2, 2, 4, 4, 4, 5, 5, 5, 8, 9, 9, 10, 10, 11, 13, 14, 16, 17, 17, 18, 20, 21, 23, 25, 26, 28, 29, 31, 33, 35, 37, 38, 40, 43, 45, 47, 49},
+		{-1, 1, 1, 2, 2, 4, 4, 6, 6, 8, 8, 8, 10, 12, 16, 12, 17, 16, 18, 21, 20, 23, 23, 25, 27, 29, 34, 34, 35, 38, 40, 43, 45, 48, 51, 53, 56, 59, 62, 65, 68},
+		{-1, 1, 1, 2, 4, 4, 4, 5, 6, 8, 8, 11, 11, 16, 16, 18, 16, 19, 21, 25, 25, 25, 34, 30, 32, 35, 37, 40, 42, 45, 48, 51, 54, 57, 60, 63, 66, 70, 74, 77, 81},
+	}
+)
+
+// rawCodewords returns the number of codewords, data and error
+// correction, that fit in a version: its area less the function patterns
+func rawCodewords(version int) int {
+	modules := (16*version+128)*version + 64
+	if version >= 2 {
+		align := version/7 + 2
+		modules -= (25*align-10)*align - 55
+		if version >= 7 {
+			modules -= 36
+		}
+	}
+	return modules / 8
+}
+
+// dataCodewords returns the number of data codewords in a version at a level
+func dataCodewords(version int, level Level) int {
+	return rawCodewords(version) - eccPerBlock[level][version]*eccBlocks[level][version]
+}
+
+// countBits returns the width of the byte mode character count
+func countBits(version int) int {
+	if version < 10 {
+		return 8
+	}
+	return 16
+}
+
+// capacity returns the number of bytes a version holds at a level
+func capacity(version int, level Level) int {
+	return (dataCodewords(version, level)*8 - 4 - countBits(version)) / 8
+}
+
+// encodeData returns the data codewords: the byte mode header, the data, a
+// terminator and padding
+func encodeData(data string, version int, level Level) []byte {
+	var bits bitBuffer
+	bits.append(0b0100, 4)
+	bits.append(len(data), countBits(version))
+	for i := 0; i < len(data); i++ {
+		bits.append(int(data[i]), 8)
+	}
+
+	capacityBits := dataCodewords(version, level) * 8
+	bits.append(0, min(4, capacityBits-len(bits)))
+	bits.append(0, (8-len(bits)%8)%8)
+	for pad := 0xEC; len(bits) < capacityBits; pad ^= 0xEC ^ 0x11 {
+		bits.append(pad, 8)
+	}
+	return bits.bytes()
+}
+
+// addECC splits the data into blocks, adds error correction to each and
+// interleaves them
+func addECC(data []byte, version int, level Level) []byte {
+	numBlocks := eccBlocks[level][version]
+	eccLen := eccPerBlock[level][version]
+	raw := rawCodewords(version)
+	numShort := numBlocks - raw%numBlocks
+	shortLen := raw / numBlocks
+
+	divisor := rsDivisor(eccLen)
+	blocks := make([][]byte, numBlocks)
+	for i, k := 0, 0; i < numBlocks; i++ {
+		n := shortLen - eccLen
+		if i >= numShort {
+			n++
+		}
+		block := append([]byte(nil), data[k:k+n]...)
+		k += n
+		ecc := rsRemainder(block, divisor)
+		if i < numShort {
+			// Placeholder so short blocks line up with long ones
+			block = append(block, 0)
+		}
+		blocks[i] = append(block, ecc...)
+	}
+
+	result := make([]byte, 0, raw)
+	for i := range blocks[0] {
+		for j, block := range blocks {
+			if i != shortLen-eccLen || j >= numShort {
+				result = append(result, block[i])
+			}
+		}
+	}
+	return result
+}
+
+// bitBuffer is a sequence of bits, one per element
+type bitBuffer []bool
+
+// append adds the low n bits of v, most significant first
+func (b *bitBuffer) append(v, n int) {
+	for i := n - 1; i >= 0; i-- {
+		*b = append(*b, v>>i&1 != 0)
+	}
+}
+
+// bytes packs the bits into bytes, most significant bit first
+func (b bitBuffer) bytes() []byte {
+	out := make([]byte, (len(b)+7)/8)
+	for i, bit := range b {
+		if bit {
+			out[i/8] |= 0x80 >> (i % 8)
+		}
+	}
+	return out
+}
@@ -0,0 +1,142 @@
+package qr
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestEncode_Decode(t *testing.T) {
+	texts := []string{
+		"",
+		"https://github.com/vnykmshr/gowsay",
+		"MOO",
+		"moo ☕ モー",
+		strings.Repeat("The cow jumped over the moon. ", 3),
+		strings.Repeat("0123456789", 11),
+	}
+
+	for _, text := range texts {
+		for level := Low; level <= High; level++ {
+			for _, invert := range []bool{false, true} {
+				opts := Options{Level: level, QuietZone: DefaultQuietZone, Invert: invert}
+				c, err := Encode(text, opts)
+				if err != nil {
+					t.Fatalf("Encode(%q, %v) error = %v", text, level, err)
+				}
+
+				got, err := decode(c.Lines(), invert)
+				if err != nil {
+					t.Fatalf("decode(Encode(%q, %v, invert=%v)) error = %v", text, level, invert, err)
+				}
+				if got.text != text || got.level != level || got.quiet != DefaultQuietZone {
+					t.Errorf("decode(Encode(%q, %v)) = %q, %v, quiet %d", text, level, got.text, got.level, got.quiet)
+				}
+				if got.version != c.Version || got.mask != c.Mask {
+					t.Errorf("decode(Encode(%q, %v)) = version %d mask %d, want %d %d",
+						text, level, got.version, got.mask, c.Version, c.Mask)
+				}
+			}
+		}
+	}
+}
+
+func TestEncode_Capacity(t *testing.T) {
+	// Fill every version the test decoder knows at every level
+	for version := 1; version <= 10; version++ {
+		for level := Low; level <= High; level++ {
+			text := strings.Repeat("moo!", capacity(version, level))[:capacity(version, level)]
+			c, err := Encode(text, Options{Level: level})
+			if err != nil || c.Version != version {
+				t.Fatalf("Encode(%d bytes, %v) = %v, %v, want version %d", len(text), level, c, err, version)
+			}
+			if got, err := decode(c.Lines(), false); err != nil || got.text != text {
+				t.Errorf("version %d-%v: decode() = %q, %v", version, level, got.text, err)
+			}
+		}
+	}
+}
+
+func TestEncode_QuietZone(t *testing.T) {
+	for _, quiet := range []int{0, 1, 2} {
+		c, err := Encode("moo", Options{QuietZone: quiet})
+		if err != nil {
+			t.Fatal(err)
+		}
+		lines := c.Lines()
+		width := c.Size() + 2*quiet
+		if len(lines) != (width+1)/2 || len([]rune(lines[0])) != width {
+			t.Errorf("quiet %d: %d lines of %d, want %d of %d", quiet, len(lines), len([]rune(lines[0])), (width+1)/2, width)
+		}
+		got, err := decode(lines, false)
+		if err != nil || got.quiet != quiet || got.text != "moo" {
+			t.Errorf("quiet %d: decode() = %+v, %v", quiet, got, err)
+		}
+	}
+	for _, quiet := range []int{-1, MaxQuietZone + 1} {
+		if _, err := Encode("moo", Options{QuietZone: quiet}); !errors.Is(err, ErrQuietZone) {
+			t.Errorf("quiet %d: Encode() = %v, want ErrQuietZone", quiet, err)
+		}
+	}
+}
+
+func TestEncode_Version(t *testing.T) {
+	tests := []struct {
+		length  int
+		level   Level
+		version int
+	}{
+		{0, Low, 1},
+		{17, Low, 1},
+		{18, Low, 2},
+		{14, Medium, 1},
+		{15, Medium, 2},
+		{7, High, 1},
+		{119, High, 10},
+		{120, High, 11},
+		{2953, Low, 40},
+		{2331, Medium, 40},
+		{1663, Quartile, 40},
+		{1273, High, 40},
+	}
+
+	for _, tt := range tests {
+		c, err := Encode(strings.Repeat("a", tt.length), Options{Level: tt.level})
+		if err != nil {
+			t.Fatalf("Encode(%d bytes, %v) error = %v", tt.length, tt.level, err)
+		}
+		if c.Version != tt.version || c.Size() != tt.version*4+17 {
+			t.Errorf("Encode(%d bytes, %v) = version %d, want %d", tt.length, tt.level, c.Version, tt.version)
+		}
+	}
+
+	if _, err := Encode(strings.Repeat("a", 2954), Options{}); !errors.Is(err, ErrTooLong) {
+		t.Errorf("Encode(2954 bytes) error = %v, want ErrTooLong", err)
+	}
+	if _, err := Encode(strings.Repeat("a", 1274), Options{Level: High}); !errors.Is(err, ErrTooLong) {
+		t.Errorf("Encode(1274 bytes, H) error = %v, want ErrTooLong", err)
+	}
+}
+
+func TestAlignmentPositions(t *testing.T) {
+	for version, want := range specAlignment {
+		if got := alignmentPositions(version); !slices.Equal(got, want) {
+			t.Errorf("alignmentPositions(%d) = %v, want %v", version, got, want)
+		}
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	for _, s := range []string{"L", "m", "Q", "h"} {
+		level, err := ParseLevel(s)
+		if err != nil || level.String() != strings.ToUpper(s) {
+			t.Errorf("ParseLevel(%q) = %v, %v", s, level, err)
+		}
+	}
+	for _, s := range []string{"", "X", "LM", "low"} {
+		if _, err := ParseLevel(s); !errors.Is(err, ErrLevel) {
+			t.Errorf("ParseLevel(%q) = %v, want ErrLevel", s, err)
+		}
+	}
+}
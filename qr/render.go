@@ -0,0 +1,42 @@
+package qr
+
+import "strings"
+
+// halfBlocks draws two rows of modules per character, indexed by
+// top<<1 | bottom
+var halfBlocks = [4]rune{' ', '▄', '▀', '█'}
+
+// Lines draws the code with its quiet zone in Unicode half blocks, one
+// character per module column and two module rows per line. Dark modules
+// are drawn as blocks, or as blanks with Options.Invert.
+func (c *Code) Lines() []string {
+	quiet := max(c.opts.QuietZone, 0)
+	width := c.size + 2*quiet
+
+	// Modules outside the code are light
+	block := func(x, y int) int {
+		x, y = x-quiet, y-quiet
+		dark := x >= 0 && x < c.size && y >= 0 && y < c.size && c.modules[y][x]
+		if dark != c.opts.Invert {
+			return 1
+		}
+		return 0
+	}
+
+	lines := make([]string, 0, (width+1)/2)
+	for y := 0; y < width; y += 2 {
+		var sb strings.Builder
+		for x := range width {
+			bottom := 0
+			if y+1 < width {
+				bottom = block(x, y+1)
+			} else if c.opts.Invert {
+				// The line below an odd-height inverted code is light too
+				bottom = 1
+			}
+			sb.WriteRune(halfBlocks[block(x, y)<<1|bottom])
+		}
+		lines = append(lines, sb.String())
+	}
+	return lines
+}
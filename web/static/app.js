@@ -31,8 +31,9 @@ async function loadOptions() {
         cowSelect.innerHTML = '<option value="random">🎲 Random</option>';
         cowsData.cows.forEach(cow => {
             const option = document.createElement('option');
-            option.value = cow;
-            option.textContent = cow;
+            option.value = cow.name;
+            option.textContent = cow.name;
+            option.title = cow.description;
             cowSelect.appendChild(option);
         });
 
@@ -49,6 +50,28 @@ async function loadOptions() {
     }
 }
 
+// Strip markdown code blocks from API output
+function stripFences(text) {
+    return text.replace(/^```\n/, '').replace(/\n```\n?$/, '');
+}
+
+// Load the cow of the day
+async function loadDaily() {
+    try {
+        const response = await fetch('/api/daily');
+        if (!response.ok) {
+            return;
+        }
+        const data = await response.json();
+
+        document.getElementById('daily').textContent = stripFences(data.output);
+        document.getElementById('daily-date').textContent = data.date;
+        document.getElementById('daily-card').style.display = 'block';
+    } catch (error) {
+        console.error('Failed to load cow of the day:', error);
+    }
+}
+
 // Randomize selections
 function randomize() {
     const cowSelect = document.getElementById('cow');
@@ -87,7 +110,8 @@ document.getElementById('cowform').addEventListener('submit', async (e) => {
         text: document.getElementById('text').value,
         cow: document.getElementById('cow').value,
         mood: document.getElementById('mood').value,
-        action: document.getElementById('action').value
+        action: document.getElementById('action').value,
+        format: 'html'
     };
 
     // Show loading state
@@ -108,13 +132,10 @@ document.getElementById('cowform').addEventListener('submit', async (e) => {
             throw new Error(error.error || 'Failed to generate cowsay');
         }
 
-        const data = await response.json();
-
-        // Strip markdown code blocks if present
-        let outputText = data.output;
-        outputText = outputText.replace(/^```\n/, '').replace(/\n```\n$/, '');
-
-        output.textContent = outputText;
+        // The server renders an escaped <pre> block, with styled spans
+        const html = await response.text();
+        const pre = new DOMParser().parseFromString(html, 'text/html').querySelector('pre');
+        output.innerHTML = pre ? pre.innerHTML : '';
         outputCard.style.display = 'block';
 
         // Smooth scroll to output
@@ -167,6 +188,7 @@ async function copyToClipboard() {
 document.addEventListener('DOMContentLoaded', () => {
     initTheme();
     loadOptions();
+    loadDaily();
 
     // Set default message
     const defaultMessages = [